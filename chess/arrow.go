@@ -0,0 +1,83 @@
+package chess
+
+import (
+	"image"
+	"image/color"
+	"math"
+)
+
+var arrowColor = color.RGBA{235, 97, 80, 255}
+
+const (
+	// the board shines through the arrow
+	arrowOpacity   = 0.8
+	arrowWidth     = 24.0
+	arrowHeadWidth = 64.0
+	arrowHeadSize  = 56.0
+)
+
+// ImageWithArrow returns the image of the board with an arrow from the origin to the target of the move.
+func (b *Board) ImageWithArrow(m Move) *image.RGBA {
+	img := b.Image()
+	from, to := b.tileCenter(m.From), b.tileCenter(m.To)
+	drawArrow(img, from, to)
+	return img
+}
+
+// tileCenter returns the pixel coordinates of the center of the tile in the board image.
+func (b *Board) tileCenter(s Square) [2]float64 {
+	y := s.Y
+	if b.turn == Dark {
+		// image is flipped for black
+		y = 7 - s.Y
+	}
+	return [2]float64{float64(s.X*128 + 64), float64(y*128 + 64)}
+}
+
+func drawArrow(img *image.RGBA, from [2]float64, to [2]float64) {
+	var (
+		dx, dy = to[0] - from[0], to[1] - from[1]
+		length = math.Hypot(dx, dy)
+	)
+
+	if length == 0 {
+		return
+	}
+
+	// unit vectors along and across the arrow
+	ux, uy := dx/length, dy/length
+	nx, ny := -uy, ux
+
+	// the shaft ends where the head starts
+	shaft := length - arrowHeadSize
+
+	// only pixels close to the arrow can be part of it
+	bounds := image.Rect(
+		int(math.Min(from[0], to[0])-arrowHeadWidth), int(math.Min(from[1], to[1])-arrowHeadWidth),
+		int(math.Max(from[0], to[0])+arrowHeadWidth), int(math.Max(from[1], to[1])+arrowHeadWidth),
+	).Intersect(img.Bounds())
+
+	for py := bounds.Min.Y; py < bounds.Max.Y; py++ {
+		for px := bounds.Min.X; px < bounds.Max.X; px++ {
+			// position of the pixel relative to the start of the arrow
+			rx, ry := float64(px)-from[0], float64(py)-from[1]
+			along := rx*ux + ry*uy
+			across := math.Abs(rx*nx + ry*ny)
+
+			inShaft := along >= 0 && along <= shaft && across <= arrowWidth/2
+			// head gets narrower towards the tip
+			inHead := along > shaft && along <= length && across <= (length-along)/arrowHeadSize*arrowHeadWidth/2
+
+			if inShaft || inHead {
+				img.SetRGBA(px, py, blend(img.RGBAAt(px, py), arrowColor, arrowOpacity))
+			}
+		}
+	}
+}
+
+func blend(bg color.RGBA, fg color.RGBA, opacity float64) color.RGBA {
+	mix := func(a uint8, b uint8) uint8 {
+		return uint8(float64(a)*(1-opacity) + float64(b)*opacity)
+	}
+	return color.RGBA{mix(bg.R, fg.R), mix(bg.G, fg.G), mix(bg.B, fg.B), 255}
+}
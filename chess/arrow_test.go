@@ -0,0 +1,30 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardImageWithArrow(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	m := chess.Move{From: chess.Square{X: 4, Y: 6}, To: chess.Square{X: 4, Y: 4}}
+	img, arrow := b.Image(), b.ImageWithArrow(m)
+
+	// arrow from e2 to e4 passes e3
+	assert.NotEqual(t, img.RGBAAt(576, 704), arrow.RGBAAt(576, 704))
+	// rest of the board is unchanged
+	assert.Equal(t, img.RGBAAt(64, 64), arrow.RGBAAt(64, 64))
+	assert.Equal(t, img.RGBAAt(448, 704), arrow.RGBAAt(448, 704))
+
+	// image is flipped for black
+	assertParse(t, b, "e4")
+	m = chess.Move{From: chess.Square{X: 4, Y: 1}, To: chess.Square{X: 4, Y: 3}}
+	img, arrow = b.Image(), b.ImageWithArrow(m)
+	assert.NotEqual(t, img.RGBAAt(576, 704), arrow.RGBAAt(576, 704))
+	assert.Equal(t, img.RGBAAt(576, 320), arrow.RGBAAt(576, 320))
+}
@@ -0,0 +1,258 @@
+package chess
+
+import "math/bits"
+
+// bitboard has one bit for each square.
+// Squares are indexed like tiles with y*8+x, so a8 is bit 0 and h1 is bit 63.
+type bitboard uint64
+
+// side identifies the pieces of a player in bitboard arrays.
+type side int
+
+const (
+	white side = iota
+	black
+)
+
+// pieceOrder maps piece indices in bitboard arrays to piece names.
+var pieceOrder = [6]PieceName{Pawn, Knight, Bishop, Rook, Queen, King}
+
+const (
+	pawnIndex = iota
+	knightIndex
+	bishopIndex
+	rookIndex
+	queenIndex
+	kingIndex
+)
+
+// directions of rays as square offsets; the first four move towards higher square indices
+var directionOffsets = [8]Square{{1, 0}, {0, 1}, {1, 1}, {-1, 1}, {-1, 0}, {0, -1}, {-1, -1}, {1, -1}}
+
+var (
+	rookRays   = []int{0, 1, 4, 5}
+	bishopRays = []int{2, 3, 6, 7}
+)
+
+// precomputed attack tables
+var (
+	knightAttacks = stepAttacks(knightOffsets)
+	kingAttacks   = stepAttacks(kingOffsets)
+	// light pawns attack towards y = 0
+	pawnAttacks = [2][64]bitboard{stepAttacks([]Square{{-1, -1}, {1, -1}}), stepAttacks([]Square{{-1, 1}, {1, 1}})}
+	rays        = computeRays()
+	// a8 is a light square
+	lightSquares = computeLightSquares()
+)
+
+func stepAttacks(offsets []Square) [64]bitboard {
+	var attacks [64]bitboard
+	for sq := 0; sq < 64; sq++ {
+		x, y := sq%8, sq/8
+		for _, o := range offsets {
+			if inBounds(x+o.X, y+o.Y) {
+				attacks[sq] |= bit(x+o.X, y+o.Y)
+			}
+		}
+	}
+	return attacks
+}
+
+func computeRays() [8][64]bitboard {
+	var rays [8][64]bitboard
+	for d, o := range directionOffsets {
+		for sq := 0; sq < 64; sq++ {
+			for x, y := sq%8+o.X, sq/8+o.Y; inBounds(x, y); x, y = x+o.X, y+o.Y {
+				rays[d][sq] |= bit(x, y)
+			}
+		}
+	}
+	return rays
+}
+
+func computeLightSquares() bitboard {
+	var bb bitboard
+	for x := 0; x < 8; x++ {
+		for y := 0; y < 8; y++ {
+			if (x+y)%2 == 0 {
+				bb |= bit(x, y)
+			}
+		}
+	}
+	return bb
+}
+
+// slidingAttacks returns the squares attacked along the given rays up to and including the first blocker.
+func slidingAttacks(sq int, occupied bitboard, directions []int) bitboard {
+	var attacks bitboard
+	for _, d := range directions {
+		ray := rays[d][sq]
+		attacks |= ray
+
+		blockers := ray & occupied
+		if blockers == 0 {
+			continue
+		}
+
+		attacks &^= rays[d][nearestBlocker(blockers, d)]
+	}
+	return attacks
+}
+
+// nearestBlocker returns the square of the blocker closest to the start of a ray in the given direction.
+func nearestBlocker(blockers bitboard, d int) int {
+	// the nearest blocker is the lowest bit for rays towards higher indices and the highest bit otherwise
+	if d >= 4 {
+		return blockers.highest()
+	}
+	return blockers.lowest()
+}
+
+func bishopAttacks(sq int, occupied bitboard) bitboard {
+	return slidingAttacks(sq, occupied, bishopRays)
+}
+
+func rookAttacks(sq int, occupied bitboard) bitboard {
+	return slidingAttacks(sq, occupied, rookRays)
+}
+
+func bit(x int, y int) bitboard {
+	return 1 << (y*8 + x)
+}
+
+// lowest returns the index of the lowest set square.
+func (bb bitboard) lowest() int {
+	return bits.TrailingZeros64(uint64(bb))
+}
+
+// highest returns the index of the highest set square.
+func (bb bitboard) highest() int {
+	return 63 - bits.LeadingZeros64(uint64(bb))
+}
+
+func (bb bitboard) count() int {
+	return bits.OnesCount64(uint64(bb))
+}
+
+func squareOf(sq int) Square {
+	return Square{X: sq % 8, Y: sq / 8}
+}
+
+func (s Square) index() int {
+	return s.Y*8 + s.X
+}
+
+func (s side) other() side {
+	return s ^ 1
+}
+
+func (s side) color() Color {
+	if s == white {
+		return Light
+	}
+	return Dark
+}
+
+func sideOf(c Color) side {
+	if c == Light {
+		return white
+	}
+	return black
+}
+
+func pieceIndex(name PieceName) int {
+	for i, n := range pieceOrder {
+		if n == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// us returns the side of the player whose turn it is.
+func (b *Board) us() side {
+	return sideOf(b.turn)
+}
+
+func (b *Board) occupancy() bitboard {
+	return b.occupied[white] | b.occupied[black]
+}
+
+// pieceAt returns the piece index and side of the piece on the given square.
+// The piece index is -1 if the square is empty.
+func (b *Board) pieceAt(sq int) (int, side) {
+	mask := bitboard(1) << sq
+	for s := white; s <= black; s++ {
+		if b.occupied[s]&mask == 0 {
+			continue
+		}
+		for i := range pieceOrder {
+			if b.pieces[s][i]&mask != 0 {
+				return i, s
+			}
+		}
+	}
+	return -1, white
+}
+
+func (b *Board) putPiece(sq int, piece int, s side) {
+	b.pieces[s][piece] |= 1 << sq
+	b.occupied[s] |= 1 << sq
+	b.hash ^= zobrist.pieces[s][piece][sq]
+}
+
+func (b *Board) removePiece(sq int) {
+	piece, s := b.pieceAt(sq)
+	if piece == -1 {
+		return
+	}
+
+	b.pieces[s][piece] &^= 1 << sq
+	b.occupied[s] &^= 1 << sq
+	b.hash ^= zobrist.pieces[s][piece][sq]
+}
+
+// attacked returns true if the given square is attacked by a piece of the given side.
+func (b *Board) attacked(sq int, by side) bool {
+	var (
+		p        = &b.pieces[by]
+		occupied = b.occupancy()
+	)
+
+	// a pawn attacks the square if a pawn of the other side on the square would attack the pawn
+	return pawnAttacks[by.other()][sq]&p[pawnIndex] != 0 ||
+		knightAttacks[sq]&p[knightIndex] != 0 ||
+		kingAttacks[sq]&p[kingIndex] != 0 ||
+		bishopAttacks(sq, occupied)&(p[bishopIndex]|p[queenIndex]) != 0 ||
+		rookAttacks(sq, occupied)&(p[rookIndex]|p[queenIndex]) != 0
+}
+
+// attackers returns the squares of all pieces of the given side that attack the given square.
+func (b *Board) attackers(sq int, by side) bitboard {
+	var (
+		p        = &b.pieces[by]
+		occupied = b.occupancy()
+	)
+
+	return pawnAttacks[by.other()][sq]&p[pawnIndex] |
+		knightAttacks[sq]&p[knightIndex] |
+		kingAttacks[sq]&p[kingIndex] |
+		bishopAttacks(sq, occupied)&(p[bishopIndex]|p[queenIndex]) |
+		rookAttacks(sq, occupied)&(p[rookIndex]|p[queenIndex])
+}
+
+// PieceSquares returns the squares of all pieces with the given name and color.
+func (b *Board) PieceSquares(name PieceName, color Color) []Square {
+	var squares []Square
+
+	piece := pieceIndex(name)
+	if piece == -1 {
+		return nil
+	}
+
+	for bb := b.pieces[sideOf(color)][piece]; bb != 0; bb &= bb - 1 {
+		squares = append(squares, squareOf(bb.lowest()))
+	}
+
+	return squares
+}
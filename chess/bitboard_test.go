@@ -0,0 +1,53 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+// middlegame position with many possible moves and captures
+const kiwipete = "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1"
+
+func BenchmarkLegalMoves(b *testing.B) {
+	board, err := chess.NewBoardFromFEN(kiwipete)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.LegalMoves()
+	}
+}
+
+func BenchmarkInCheck(b *testing.B) {
+	board, err := chess.NewBoardFromFEN(kiwipete)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.InCheck()
+	}
+}
+
+func BenchmarkParseGame(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		if _, err := chess.NewGame("e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3 Nb8 d4 Nbd7"); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestBoardPieceSquares(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assert.Equal(t, []chess.Square{{X: 1, Y: 7}, {X: 6, Y: 7}}, b.PieceSquares(chess.Knight, chess.Light))
+	assert.Equal(t, []chess.Square{{X: 4, Y: 0}}, b.PieceSquares(chess.King, chess.Dark))
+	assert.Len(t, b.PieceSquares(chess.Pawn, chess.Dark), 8)
+}
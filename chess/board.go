@@ -7,7 +7,6 @@ import (
 	"image/png"
 	"log"
 	"os"
-	"regexp"
 	"strings"
 
 	"golang.org/x/image/font"
@@ -17,9 +16,21 @@ import (
 )
 
 type Board struct {
-	tiles          [8][8]*Piece
+	pieces         [2][6]bitboard
+	occupied       [2]bitboard
 	turn           Color
-	Moves          []string
+	castling       castlingRights
+	castleFiles    [2]castleFiles
+	chess960       bool
+	variant        Variant
+	enPassant      *Square
+	halfmoveClock  int
+	fullmoveNumber int
+	startFEN       string
+	hash           uint64
+	positions      map[uint64]int
+	history        []boardState
+	Moves          []Move
 	moveIndicators []Tile
 }
 
@@ -28,7 +39,12 @@ type Tile struct {
 }
 
 func NewBoard() *Board {
-	board := &Board{turn: Light}
+	board := &Board{
+		turn:           Light,
+		castling:       allCastlingRights,
+		castleFiles:    [2]castleFiles{standardCastleFiles, standardCastleFiles},
+		fullmoveNumber: 1,
+	}
 
 	board.mustSetPiece(Rook, Light, "a1")
 	board.mustSetPiece(Knight, Light, "b1")
@@ -64,6 +80,9 @@ func NewBoard() *Board {
 	board.mustSetPiece(Pawn, Dark, "g7")
 	board.mustSetPiece(Pawn, Dark, "h7")
 
+	board.hash = board.computeHash()
+	board.recordPosition()
+
 	return board
 }
 
@@ -110,8 +129,7 @@ func (b *Board) Image() *image.RGBA {
 			bg = image.NewUniform(getTileColor(b, xi, yi))
 			draw.Draw(img, rect, bg, p, draw.Src)
 
-			piece = b.tiles[xi][yi]
-			if piece != nil {
+			if piece = b.pieceImage(xi, yi); piece != nil {
 				pieceImg := piece.Image
 				if b.turn == Dark {
 					pieceImg = flipImage(pieceImg)
@@ -139,6 +157,22 @@ func (b *Board) Image() *image.RGBA {
 	return img
 }
 
+// pieceImage returns the piece with its image on the given tile or nil if the tile is empty.
+func (b *Board) pieceImage(x, y int) *Piece {
+	name, s := b.pieceAt(y*8 + x)
+	if name == -1 {
+		return nil
+	}
+
+	piece, err := cachedPiece(pieceOrder[name], s)
+	if err != nil {
+		log.Printf("cannot draw %s: %v", pieceNames[pieceOrder[name]], err)
+		return nil
+	}
+
+	return piece
+}
+
 func drawCoordinate(img *image.RGBA, x, y int, flipped bool) {
 	if x != 7 && y != 7 {
 		return
@@ -253,25 +287,29 @@ func loadFontFace(path string) (font.Face, error) {
 
 func (b *Board) SetPiece(name PieceName, color Color, position string) error {
 	var (
-		piece *Piece
-		x     int
-		y     int
-		err   error
+		x   int
+		y   int
+		err error
 	)
 
 	if len(position) != 2 {
 		return fmt.Errorf("invalid position: %s", position)
 	}
 
-	if piece, err = NewPiece(name, color); err != nil {
-		return err
+	if color != Light && color != Dark {
+		return fmt.Errorf("invalid color: %v", color)
+	}
+
+	if pieceIndex(name) == -1 {
+		return fmt.Errorf("invalid piece: %s", name)
 	}
 
 	if x, y, err = getXY(position); err != nil {
 		return err
 	}
 
-	b.tiles[x][y] = piece
+	b.removePiece(y*8 + x)
+	b.putPiece(y*8+x, pieceIndex(name), sideOf(color))
 
 	return nil
 }
@@ -283,127 +321,69 @@ func (b *Board) AlgebraicNotation() string {
 
 	var text string
 	for i, m := range b.Moves {
-		if i%2 == 0 {
-			text += fmt.Sprintf("%d.%s", i/2+1, m)
+		ply := b.plyOffset() + i
+		if ply%2 == 0 {
+			text += fmt.Sprintf("%d.%s", ply/2+1, m.SAN)
+		} else if i == 0 {
+			text += fmt.Sprintf("%d...%s ", ply/2+1, m.SAN)
 		} else {
-			text += fmt.Sprintf(" %s ", m)
+			text += fmt.Sprintf(" %s ", m.SAN)
 		}
 	}
 	return fmt.Sprintf("`%s`", text)
 }
 
-func (b *Board) Parse(pgn string) error {
-	var (
-		moves = strings.Split(strings.Trim(pgn, " "), " ")
-		re    = regexp.MustCompile(`[0-9]+\.`)
-		err   error
-	)
-
-	for _, move := range moves {
-		move = strings.Trim(move, " ")
-		if move == "" {
-			continue
-		}
-
-		// parse algebraic notation with numbers
-		move = re.ReplaceAllString(move, "")
-
-		if err = b.Move(move); err != nil {
-			return err
-		}
+// plyOffset returns the number of half moves played before the first recorded move.
+// This is only non-zero for games started from a custom position.
+func (b *Board) plyOffset() int {
+	offset := 2*(b.fullmoveNumber-1) - len(b.Moves)
+	if b.turn == Dark {
+		offset++
 	}
+	return offset
+}
 
-	return nil
+// Turn returns the color of the player whose turn it is.
+func (b *Board) Turn() Color {
+	return b.turn
 }
 
-func (b *Board) Move(move string) error {
+// Parse executes the moves of the main line in the given PGN movetext.
+func (b *Board) Parse(pgn string) error {
 	var (
-		to    string
-		piece string
-		// if the move is ambiguous, the originating square rank must be given
-		// see https://en.wikipedia.org/wiki/Algebraic_notation_(chess)#Disambiguating_moves
-		fromX          int
-		fromY          int
-		promotion      string
-		castle         = false
-		collisionPiece *Piece
-		err            error
+		game *PGNGame
+		err  error
 	)
 
-	if parts := strings.Split(move, "="); len(parts) > 1 {
-		promotion = parts[1]
-		move = parts[0]
-	}
-
-	if piece, fromX, fromY, to, err = parseMove(move); err != nil {
+	if game, err = ParsePGN(pgn); err != nil {
 		return err
 	}
 
-	if move == "O-O" || move == "O-O-O" {
-		castle = true
-		if b.turn == Dark {
-			fromY = 0
-			if move == "O-O" {
-				to = "g8"
-			} else {
-				to = "c8"
-			}
-		}
-	}
-
-	// TODO: parse ambiguous captures for all pieces
-	// TODO: parse checkmates e.g. e5#
-
-	move_ := func() error {
-
-		// collision detection
-		if collisionPiece, err = b.getCollision(to); err != nil {
-			return fmt.Errorf("invalid move %s: %v", move, err)
-		} else if collisionPiece != nil {
-			return fmt.Errorf("invalid move %s: position %s blocked by %s", move, to, collisionPiece)
-		}
-
-		switch strings.ToLower(piece) {
-		case "p":
-			return b.movePawn(to, fromX, fromY, promotion)
-		case "r":
-			return b.moveRook(to, false, fromX, fromY)
-		case "b":
-			return b.moveBishop(to, false)
-		case "n":
-			return b.moveKnight(to, fromX, fromY)
-		case "q":
-			return b.moveQueen(to, fromX, fromY)
-		case "k":
-			return b.moveKing(to, castle)
-		default:
-			return fmt.Errorf("invalid move %s: %v", move, err)
+	for _, move := range game.MainLine() {
+		if _, err = b.Move(move); err != nil {
+			return err
 		}
 	}
 
-	if err = move_(); err != nil {
-		return err
-	}
+	return nil
+}
 
-	// is current player in check after move?
-	if b.InCheck() {
-		return fmt.Errorf("invalid move %s: king is in check", move)
-	}
+// Move executes the given move in SAN and returns it.
+func (b *Board) Move(move string) (Move, error) {
+	var (
+		m   Move
+		err error
+	)
 
-	if b.turn == Light {
-		b.turn = Dark
-	} else {
-		b.turn = Light
+	if status := b.Status(); status != Ongoing {
+		return Move{}, &GameOverError{Move: move, Status: status}
 	}
 
-	// make sure the move is marked as a check if it was
-	if b.InCheck() && !strings.HasSuffix(move, "+") && !strings.HasSuffix(move, "#") {
-		move += "+"
+	if m, err = b.resolveMove(move); err != nil {
+		return Move{}, b.withSuggestions(err, move)
 	}
 
-	b.Moves = append(b.Moves, move)
-
-	return nil
+	return b.makeMove(m), nil
 }
 
 func parseMove(move string) (string, int, int, string, error) {
@@ -418,20 +398,12 @@ func parseMove(move string) (string, int, int, string, error) {
 	move = strings.TrimSuffix(move, "+")
 	move = strings.TrimSuffix(move, "#")
 
-	if move == "O-O" {
-		return "K", 5, 7, "g1", nil
-	}
-
-	if move == "O-O-O" {
-		return "K", 5, 7, "c1", nil
-	}
-
 	if strings.Contains(move, "x") {
 		return parseCaptureMove(move)
 	}
 
 	if len(move) < 2 {
-		return piece, fromX, fromY, to, fmt.Errorf("invalid move: %s", move)
+		return piece, fromX, fromY, to, parseError("move", "%s", move)
 	}
 
 	if len(move) == 2 {
@@ -472,15 +444,22 @@ func parseMove(move string) (string, int, int, string, error) {
 			return piece, -1, toRank(rune(from[0])), to, nil
 		}
 
-		return "", -1, -1, "", fmt.Errorf("invalid move: %s", move)
+		return "", -1, -1, "", parseError("move", "%s", move)
 	}
 
 	if len(from) == 2 {
 		// file and rank given
-		return piece, toFile(rune(from[0])), toRank(rune(from[1])), to, nil
+		fromX = toFile(rune(from[0]))
+		fromY = toRank(rune(from[1]))
+
+		if fromX == -1 || fromY == -1 {
+			return "", -1, -1, "", parseError("move", "%s", move)
+		}
+
+		return piece, fromX, fromY, to, nil
 	}
 
-	return "", -1, -1, "", fmt.Errorf("invalid move: %s", move)
+	return "", -1, -1, "", parseError("move", "%s", move)
 }
 
 func parseCaptureMove(move string) (string, int, int, string, error) {
@@ -493,11 +472,17 @@ func parseCaptureMove(move string) (string, int, int, string, error) {
 		to    = parts[1]
 	)
 
+	if len(from) == 0 {
+		return "", -1, -1, "", parseError("move", "%s", move)
+	}
+
 	if len(from) == 1 {
-		// pawn move with rank given (exd4) or piece move (Nxe5)
+		// pawn move with file given (exd4) or piece move (Nxe5)
 		if strings.ToLower(from) == from {
 			piece = "p"
-			fromX = toFile(rune(from[0]))
+			if fromX = toFile(rune(from[0])); fromX == -1 {
+				return "", -1, -1, "", parseError("move", "%s", move)
+			}
 			return piece, fromX, fromY, to, nil
 		}
 
@@ -514,7 +499,7 @@ func parseCaptureMove(move string) (string, int, int, string, error) {
 		fromY = toRank(rune(from[1]))
 
 		if fromX == -1 && fromY == -1 {
-			return "", -1, -1, "", fmt.Errorf("invalid move: %s", move)
+			return "", -1, -1, "", parseError("move", "%s", move)
 		}
 
 		return piece, fromX, fromY, to, nil
@@ -523,16 +508,16 @@ func parseCaptureMove(move string) (string, int, int, string, error) {
 	if len(from) == 3 {
 		// both file and rank given
 		fromX = toFile(rune(from[1]))
-		fromY = toRank(rune(from[1]))
+		fromY = toRank(rune(from[2]))
 
 		if fromX == -1 || fromY == -1 {
-			return "", -1, -1, "", fmt.Errorf("invalid move: %s", move)
+			return "", -1, -1, "", parseError("move", "%s", move)
 		}
 
 		return piece, fromX, fromY, to, nil
 	}
 
-	return "", -1, -1, "", fmt.Errorf("invalid move: %s", move)
+	return "", -1, -1, "", parseError("move", "%s", move)
 }
 
 func toFile(r rune) int {
@@ -549,846 +534,49 @@ func toRank(r rune) int {
 	return -1
 }
 
-func (b *Board) validateMove(search PieceName, fromX int, fromY int) func(*Piece, int, int) bool {
-	return func(p *Piece, xPrev int, yPrev int) bool {
-		// does piece originate from given origin?
-		from := (fromX == -1 || xPrev == fromX) && (fromY == -1 || yPrev == fromY)
-
-		// is this the piece we're looking for?
-		found := p != nil && p.Name == search && p.Color == b.turn
-
-		return from && found
-	}
-}
-
+// InCheck returns true if the king of the player whose turn it is is attacked.
 func (b *Board) InCheck() bool {
-	var (
-		kingX, kingY int
-		p            *Piece
-		x, y         int
-	)
-
-outerLoop:
-	// find king
-	for y = 0; y < 8; y++ {
-		for x = 0; x < 8; x++ {
-			p := b.getPiece(x, y)
-			if p != nil && p.Name == King && p.Color == b.turn {
-				kingX = x
-				kingY = y
-				break outerLoop
-			}
-		}
-	}
-
-	// check if any piece can attack king
-
-	// ^
-	x = kingX
-	y = kingY
-	for y = kingY - 1; y >= 0; y-- {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Rook || p.Name == Queen) {
-			return true
-		}
-
-		// some other piece is blocking the way
-		break
-	}
-
-	// ^>
-	x = kingX
-	y = kingY
-	for x, y = kingX+1, kingY-1; x < 8 && y >= 0; x, y = x+1, y-1 {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Bishop || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// >
-	x = kingX
-	y = kingY
-	for x = kingX + 1; x < 8; x++ {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Rook || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// v>
-	x = kingX
-	y = kingY
-	for x, y = kingX+1, kingY+1; x < 8 && y < 8; x, y = x+1, y+1 {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Bishop || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// v
-	x = kingX
-	y = kingY
-	for y = kingY + 1; y < 8; y++ {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Rook || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// <v
-	x = kingX
-	y = kingY
-	for x, y = kingX-1, kingY+1; x >= 0 && y < 8; x, y = x-1, y+1 {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Bishop || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// <
-	x = kingX
-	y = kingY
-	for x = kingX - 1; x >= 0; x-- {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Rook || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// <^
-	x = kingX
-	y = kingY
-	for x, y = kingX-1, kingY-1; x >= 0 && y >= 0; x, y = x-1, y-1 {
-		p = b.getPiece(x, y)
-
-		if p == nil {
-			continue
-		}
-
-		if p.Color != b.turn && (p.Name == Bishop || p.Name == Queen) {
-			return true
-		}
-
-		break
-	}
-
-	// check for knights
-	x = kingX + 1
-	y = kingY - 2
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX + 2
-	y = kingY - 1
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX + 2
-	y = kingY + 1
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX + 1
-	y = kingY + 2
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX - 1
-	y = kingY + 2
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX - 2
-	y = kingY + 1
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX - 2
-	y = kingY - 1
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	x = kingX - 1
-	y = kingY - 2
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Knight {
-		return true
-	}
-
-	// check for pawns
-	x = kingX - 1
-	if b.turn == Light {
-		y = kingY - 1
-	} else {
-		y = kingY + 1
-	}
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Pawn {
-		return true
-	}
-
-	x = kingX + 1
-	if b.turn == Light {
-		y = kingY - 1
-	} else {
-		y = kingY + 1
-	}
-	p = b.getPiece(x, y)
-	if p != nil && p.Color != b.turn && p.Name == Pawn {
-		return true
-	}
-
-	return false
+	king := b.pieces[b.us()][kingIndex]
+	return king != 0 && b.attacked(king.lowest(), b.us().other())
 }
 
-func (b *Board) movePawn(position string, fromX int, fromY int, promotion string) error {
-	var (
-		toX   int
-		toY   int
-		yPrev int
-		piece *Piece
-		err   error
-	)
-
-	if toX, toY, err = getXY(position); err != nil {
-		return err
-	}
-
-	if fromX != -1 {
-		if b.turn == Light {
-			fromY = toY + 1
-		} else {
-			fromY = toY - 1
-		}
-
-		piece = b.getPiece(fromX, fromY)
-		if piece == nil || piece.Name != Pawn || piece.Color != b.turn {
-			// not your pawn
-			return fmt.Errorf("invalid capture move for pawn: %s", position)
-		}
-
-		if fromX != toX-1 && fromX != toX+1 || (b.turn == Light && fromY != toY+1) || (b.turn == Dark && fromY != toY-1) {
-			// invalid capture move
-			return fmt.Errorf("invalid capture move for pawn: %s", position)
-		}
-
-		b.tiles[fromX][fromY] = nil
-		b.tiles[toX][toY] = piece
-		if promotion != "" {
-			return b.promotePawn(toX, toY, promotion)
-		}
-		b.moveIndicators = []Tile{{fromX, fromY}, {toX, toY}}
-		return nil
-	}
-
-	// TODO: assert move is valid:
-	//   * 2 moves from start position
-	//   * 1 move otherwise
-	//   * diagonal if attacking
-	//   * no collision with other pieces
-
-	if b.turn == Light {
-		yPrev = toY + 1
-	} else {
-		yPrev = toY - 1
-	}
-
-	piece = b.tiles[toX][yPrev]
-	if piece != nil && piece.Name == Pawn && piece.Color == b.turn {
-		b.tiles[toX][yPrev] = nil
-		b.tiles[toX][toY] = piece
-		if promotion != "" {
-			return b.promotePawn(toX, toY, promotion)
-		}
-		b.moveIndicators = []Tile{{toX, yPrev}, {toX, toY}}
-		return nil
-	}
-
-	if b.turn == Light {
-		yPrev = toY + 2
-	} else {
-		yPrev = toY - 2
-	}
-
-	piece = b.tiles[toX][yPrev]
-	if piece != nil && piece.Name == Pawn && piece.Color == b.turn {
-		b.tiles[toX][yPrev] = nil
-		b.tiles[toX][toY] = piece
-		if promotion != "" {
-			return b.promotePawn(toX, toY, promotion)
-		}
-		b.moveIndicators = []Tile{{toX, yPrev}, {toX, toY}}
-		return nil
-	}
-
-	return fmt.Errorf("no pawn found that can move to %s", position)
-}
-
-func (b *Board) promotePawn(x int, y int, name string) error {
-	switch strings.ToLower(name) {
-	case "q":
-		b.tiles[x][y] = &Piece{Name: Queen, Color: b.turn}
-	case "r":
-		b.tiles[x][y] = &Piece{Name: Rook, Color: b.turn}
-	case "b":
-		b.tiles[x][y] = &Piece{Name: Bishop, Color: b.turn}
-	case "n":
-		b.tiles[x][y] = &Piece{Name: Knight, Color: b.turn}
-	default:
-		return fmt.Errorf("invalid promotion: %s", name)
-	}
-
-	return nil
-}
-
-func (b *Board) moveRook(position string, queen bool, fromX int, fromY int) error {
-	var (
-		x         int
-		y         int
-		xPrev     int
-		yPrev     int
-		p         *Piece
-		validPrev []*Square
-		err       error
-	)
-
-	if x, y, err = getXY(position); err != nil {
-		return err
-	}
-
-	checkRookMove := b.validateMove(Rook, fromX, fromY)
-	checkQueenMove := b.validateMove(Queen, fromX, fromY)
-	checkMove := func(p *Piece, xPrev int, yPrev int) bool {
-		return (!queen && checkRookMove(p, xPrev, yPrev)) || (queen && checkQueenMove(p, xPrev, yPrev))
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev++
-		p = b.getPiece(xPrev, yPrev)
-		if p != nil {
-			if checkMove(p, xPrev, yPrev) {
-				validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-			}
-			break
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		yPrev--
-		p = b.getPiece(xPrev, yPrev)
-		if p != nil {
-			if checkMove(p, xPrev, yPrev) {
-				validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-			} else {
-				break
-			}
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev--
-		p = b.getPiece(xPrev, yPrev)
-		if p != nil {
-			if checkMove(p, xPrev, yPrev) {
-				validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-			} else {
-				break
-			}
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		yPrev++
-		p = b.getPiece(xPrev, yPrev)
-		if p != nil {
-			if checkMove(p, xPrev, yPrev) {
-				validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-			} else {
-				break
-			}
-		}
-	}
-
-	if len(validPrev) > 1 {
-		return fmt.Errorf("move ambiguous: %d rooks can move to %s", len(validPrev), position)
-	}
-
-	if len(validPrev) == 1 {
-		xPrev = validPrev[0].X
-		yPrev = validPrev[0].Y
-		p = b.getPiece(xPrev, yPrev)
-		b.tiles[x][y] = p
-		b.tiles[xPrev][yPrev] = nil
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	return fmt.Errorf("no rook found that can move to %s", position)
-}
-
-func (b *Board) moveBishop(position string, queen bool) error {
-	var (
-		x     int
-		y     int
-		xPrev int
-		yPrev int
-		piece *Piece
-		err   error
-	)
-
-	if x, y, err = getXY(position); err != nil {
-		return err
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev++
-		yPrev--
-		piece = b.getPiece(xPrev, yPrev)
-		if piece != nil {
-			if ((!queen && piece.Name == Bishop) || (queen && piece.Name == Queen)) && piece.Color == b.turn {
-				b.tiles[xPrev][yPrev] = nil
-				b.tiles[x][y] = piece
-				b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-				return nil
-			} else {
-				// direction blocked by other piece
-				break
-			}
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev++
-		yPrev++
-		piece = b.getPiece(xPrev, yPrev)
-		if piece != nil {
-			if ((!queen && piece.Name == Bishop) || (queen && piece.Name == Queen)) && piece.Color == b.turn {
-				b.tiles[xPrev][yPrev] = nil
-				b.tiles[x][y] = piece
-				b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-				return nil
-			} else {
-				break
-			}
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev--
-		yPrev++
-		piece = b.getPiece(xPrev, yPrev)
-		if piece != nil {
-			if ((!queen && piece.Name == Bishop) || (queen && piece.Name == Queen)) && piece.Color == b.turn {
-				b.tiles[xPrev][yPrev] = nil
-				b.tiles[x][y] = piece
-				b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-				return nil
-			} else {
-				break
-			}
-		}
-	}
-
-	xPrev = x
-	yPrev = y
-	for xPrev >= 0 && xPrev < 8 && yPrev >= 0 && yPrev < 8 {
-		xPrev--
-		yPrev--
-		piece = b.getPiece(xPrev, yPrev)
-		if piece != nil {
-			if ((!queen && piece.Name == Bishop) || (queen && piece.Name == Queen)) && piece.Color == b.turn {
-				b.tiles[xPrev][yPrev] = nil
-				b.tiles[x][y] = piece
-				b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-				return nil
-			} else {
-				break
-			}
-		}
+func (b *Board) mustSetPiece(name PieceName, color Color, position string) {
+	if err := b.SetPiece(name, color, position); err != nil {
+		log.Fatalf("cannot set piece %s: %v", name, err)
 	}
-
-	return fmt.Errorf("no bishop found that can move to %s", position)
 }
 
-func (b *Board) moveKnight(position string, fromX int, fromY int) error {
-	var (
-		x         int
-		y         int
-		xPrev     int
-		yPrev     int
-		p         *Piece
-		validPrev []*Square
-		err       error
-	)
-
-	if x, y, err = getXY(position); err != nil {
-		return err
-	}
-
-	checkMove := b.validateMove(Knight, fromX, fromY)
-
-	xPrev = x + 1
-	yPrev = y - 2
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x + 2
-	yPrev = y - 1
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x + 2
-	yPrev = y + 1
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x + 1
-	yPrev = y + 2
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x - 1
-	yPrev = y + 2
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x - 2
-	yPrev = y + 1
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x - 2
-	yPrev = y - 1
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	xPrev = x - 1
-	yPrev = y - 2
-	p = b.getPiece(xPrev, yPrev)
-	if checkMove(p, xPrev, yPrev) {
-		validPrev = append(validPrev, &Square{X: xPrev, Y: yPrev})
-	}
-
-	if len(validPrev) > 1 {
-		return fmt.Errorf("move ambiguous: %d knights can move to %s", len(validPrev), position)
-	}
-
-	if len(validPrev) == 1 {
-		xPrev = validPrev[0].X
-		yPrev = validPrev[0].Y
-		p = b.getPiece(xPrev, yPrev)
-		b.tiles[x][y] = p
-		b.tiles[xPrev][yPrev] = nil
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	return fmt.Errorf("no knight found that can move to %s", position)
-}
-
-func (b *Board) moveQueen(position string, fromX int, fromY int) error {
+// At returns the piece on the given square or nil if the square is empty or does not exist.
+// The image of the piece is loaded on first use and stays nil if it can't be loaded.
+func (b *Board) At(position string) *Piece {
 	var (
+		x   int
+		y   int
 		err error
 	)
-
-	// TODO: queen bishop moves might be ambiguous if there are multiple queens
-	if err = b.moveBishop(position, true); err == nil {
-		return nil
-	}
-
-	if err = b.moveRook(position, true, fromX, fromY); err == nil {
-		return nil
-	}
-
-	return fmt.Errorf("no queen found that can move to %s", position)
-}
-
-func (b *Board) moveKing(position string, castle bool) error {
-	var (
-		x     int
-		y     int
-		xPrev int
-		yPrev int
-		piece *Piece
-		err   error
-	)
-
 	if x, y, err = getXY(position); err != nil {
-		return err
-	}
-
-	if castle {
-		// TODO: check if castle is allowed
-
-		y := 7
-		if b.turn == Dark {
-			y = 0
-		}
-
-		if (b.turn == Light && position == "g1") || (b.turn == Dark && position == "g8") {
-			// kingside castle
-
-			king := b.getPiece(4, y)
-			if king == nil || king.Color != b.turn || king.Name != King {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			if b.getPiece(5, y) != nil {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			if b.getPiece(6, y) != nil {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			rook := b.getPiece(7, y)
-			if rook == nil || rook.Color != b.turn || rook.Name != Rook {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			b.tiles[6][y] = king
-			b.tiles[4][y] = nil
-			b.tiles[5][y] = rook
-			b.tiles[7][y] = nil
-
-			b.moveIndicators = []Tile{{4, y}, {x, y}, {5, y}, {7, y}}
-
-			return nil
-		}
-
-		if (b.turn == Light && position == "c1") || (b.turn == Dark && position == "c8") {
-			// queenside castle
-
-			king := b.getPiece(4, y)
-			if king == nil || king.Color != b.turn || king.Name != King {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			if b.getPiece(3, y) != nil {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			if b.getPiece(2, y) != nil {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			if b.getPiece(1, y) != nil {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			rook := b.getPiece(0, y)
-			if rook == nil || rook.Color != b.turn || rook.Name != Rook {
-				return fmt.Errorf("invalid castle move")
-			}
-
-			b.tiles[2][y] = king
-			b.tiles[4][y] = nil
-			b.tiles[3][y] = rook
-			b.tiles[0][y] = nil
-
-			b.moveIndicators = []Tile{{2, y}, {4, y}, {3, y}, {0, y}}
-
-			return nil
-		}
-	}
-
-	// ^
-	xPrev = x + 0
-	yPrev = y - 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	// ^>
-	xPrev = x + 1
-	yPrev = y - 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	// >
-	xPrev = x + 1
-	yPrev = y + 0
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	// v>
-	xPrev = x + 1
-	yPrev = y + 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	// v
-	xPrev = x + 0
-	yPrev = y + 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
-	}
-
-	// <v
-	xPrev = x - 1
-	yPrev = y + 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
 		return nil
 	}
 
-	// <
-	xPrev = x - 1
-	yPrev = y + 0
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
+	p := b.pieceOn(y*8 + x)
+	if p == nil {
 		return nil
 	}
 
-	// <^
-	xPrev = x - 1
-	yPrev = y - 1
-	piece = b.getPiece(xPrev, yPrev)
-	if piece != nil && piece.Name == King && piece.Color == b.turn {
-		b.tiles[xPrev][yPrev] = nil
-		b.tiles[x][y] = piece
-		b.moveIndicators = []Tile{{xPrev, yPrev}, {x, y}}
-		return nil
+	if cached, err := cachedPiece(p.Name, sideOf(p.Color)); err == nil {
+		return cached
 	}
 
-	return fmt.Errorf("no king found that can move to %s", position)
-}
-
-func (b *Board) mustSetPiece(name PieceName, color Color, position string) {
-	if err := b.SetPiece(name, color, position); err != nil {
-		log.Fatalf("cannot set piece %s: %v", name, err)
-	}
+	return p
 }
 
-func (b *Board) At(position string) *Piece {
-	var (
-		x   int
-		y   int
-		err error
-	)
-	if x, y, err = getXY(position); err != nil {
+// pieceOn returns the piece on the given square without its image or nil if the square is empty.
+func (b *Board) pieceOn(sq int) *Piece {
+	piece, s := b.pieceAt(sq)
+	if piece == -1 {
 		return nil
 	}
-	return b.tiles[x][y]
+	return &Piece{Name: pieceOrder[piece], Color: s.color()}
 }
 
 func getXY(position string) (int, int, error) {
@@ -1422,35 +610,6 @@ func getXY(position string) (int, int, error) {
 	return x, y, nil
 }
 
-func (b *Board) getPiece(x int, y int) *Piece {
-	if x < 0 || x >= 8 || y < 0 || y >= 8 {
-		return nil
-	}
-	return b.tiles[x][y]
-}
-
-func (b *Board) getCollision(position string) (*Piece, error) {
-	var (
-		x, y      int
-		p         *Piece
-		collision bool
-		err       error
-	)
-	if x, y, err = getXY(position); err != nil {
-		return nil, err
-	}
-
-	p = b.getPiece(x, y)
-
-	// check if position is occupied by own piece
-	collision = p != nil && p.Color == b.turn
-	if collision {
-		return p, nil
-	}
-
-	return nil, nil
-}
-
 func getTileColor(b *Board, x, y int) Color {
 
 	lightTile := x%2 == y%2
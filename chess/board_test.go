@@ -131,6 +131,40 @@ func TestBoardMovePawnCapture(t *testing.T) {
 	assertPiece(t, b, "e4", chess.Pawn, chess.Light)
 }
 
+func TestBoardMovePawnEnPassant(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 a6 e5 d5 exd6")
+
+	assertPiece(t, b, "d6", chess.Pawn, chess.Light)
+	assertNoPiece(t, b, "d5")
+	assertNoPiece(t, b, "e5")
+
+	b = chess.NewBoard()
+
+	assertParse(t, b, "a3 e5 a4 e4 d4 exd3")
+
+	assertPiece(t, b, "d3", chess.Pawn, chess.Dark)
+	assertNoPiece(t, b, "d4")
+	assertNoPiece(t, b, "e4")
+
+	// en passant is only allowed immediately after the two-square pawn push
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 a6 e5 d5 a3 h6")
+
+	assertMoveError(t, b, "exd6", "invalid move exd6: pawn can't capture on d6")
+
+	// pawn did not skip the square
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 d6 e5 d5")
+
+	assertMoveError(t, b, "exd6", "invalid move exd6: pawn can't capture on d6")
+}
+
 func TestBoardPawnPromotion(t *testing.T) {
 	t.Parallel()
 
@@ -228,7 +262,7 @@ func TestBoardMoveKnightInvalid(t *testing.T) {
 
 	b = chess.NewBoard()
 	assertParse(t, b, "e4 e5 Nf3 d6 Nc3 d5 Nb5 d4")
-	assertMoveError(t, b, "Nxd4", "move ambiguous: 2 knights can move to d4")
+	assertMoveError(t, b, "Nxd4", "move ambiguous: 2 knights can move to d4 from b5 and f3")
 	assertMoveError(t, b, "N4xd4", "no knight found that can move to d4")
 	// disambiguate via file
 	assertParse(t, b, "Nfxd4")
@@ -386,7 +420,7 @@ func TestBoardMoveRookInvalid(t *testing.T) {
 
 	assertParse(t, b, "a4 e6 h4 e5 Ra3 e4")
 
-	assertMoveError(t, b, "Rh3", "move ambiguous: 2 rooks can move to h3")
+	assertMoveError(t, b, "Rh3", "move ambiguous: 2 rooks can move to h3 from a3 and h1")
 	assertParse(t, b, "Rhh3")
 }
 
@@ -464,7 +498,7 @@ func TestBoardCheck(t *testing.T) {
 	assertParse(t, b, "e4 e5 Qh5 Nc6 Qxf7")
 
 	assert.True(t, b.InCheck())
-	assert.True(t, strings.HasSuffix(b.Moves[len(b.Moves)-1], "+"), "check move should end with +")
+	assert.True(t, strings.HasSuffix(b.Moves[len(b.Moves)-1].SAN, "+"), "check move should end with +")
 
 	assertMoveError(t, b, "Nf6", "invalid move Nf6: king is in check")
 	assertMoveError(t, b, "Ke7", "invalid move Ke7: king is in check")
@@ -483,9 +517,106 @@ func TestBoardCheckmate(t *testing.T) {
 	assertParse(t, b, "f3 e6 g4 Qh4#")
 
 	assert.True(t, b.InCheck())
-	assert.True(t, strings.HasSuffix(b.Moves[len(b.Moves)-1], "#"), "checkmate move should end with #")
+	assert.True(t, strings.HasSuffix(b.Moves[len(b.Moves)-1].SAN, "#"), "checkmate move should end with #")
+	assert.Equal(t, chess.Checkmate, b.Status())
+	assert.Equal(t, chess.BlackWins, b.Result())
 
-	assertMoveError(t, b, "a3", "invalid move a3: king is in check")
+	assertMoveError(t, b, "a3", "invalid move a3: game ended by checkmate")
+
+	// the suffix is added to the recorded move even if it was not given
+	b = chess.NewBoard()
+	assertParse(t, b, "f3 e6 g4 Qh4")
+	assert.Equal(t, "Qh4#", b.Moves[len(b.Moves)-1].SAN)
+}
+
+func TestBoardStalemate(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assert.Equal(t, chess.Ongoing, b.Status())
+	assert.Equal(t, chess.NoResult, b.Result())
+
+	// fastest known stalemate by Sam Loyd
+	assertParse(t, b, "e3 a5 Qh5 Ra6 Qxa5 h5 h4 Rah6 Qxc7 f6 Qxd7+ Kf7 Qxb7 Qd3 Qxb8 Qh7 Qxc8 Kg6 Qe6")
+
+	assert.False(t, b.InCheck())
+	assert.Equal(t, chess.Stalemate, b.Status())
+	assert.Equal(t, chess.Draw, b.Result())
+
+	assertMoveError(t, b, "Kg5", "invalid move Kg5: game ended by stalemate")
+}
+
+func TestBoardInsufficientMaterial(t *testing.T) {
+	t.Parallel()
+
+	for fen, status := range map[string]chess.Status{
+		"4k3/8/8/8/8/8/8/4K3 w - - 0 1":    chess.InsufficientMaterial,
+		"4k3/8/8/8/8/8/8/4K1N1 w - - 0 1":  chess.InsufficientMaterial,
+		"4k3/8/8/8/8/8/8/4KB2 w - - 0 1":   chess.InsufficientMaterial,
+		"4kb2/8/8/8/8/8/8/4KB2 w - - 0 1":  chess.Ongoing,
+		"4k1b1/8/8/8/8/8/8/4KB2 w - - 0 1": chess.InsufficientMaterial,
+		"4k3/8/8/8/8/8/8/4KNN1 w - - 0 1":  chess.Ongoing,
+		"4k3/8/8/8/8/8/8/4KBN1 w - - 0 1":  chess.Ongoing,
+		"4k3/8/8/8/8/8/8/4K2R w - - 0 1":   chess.Ongoing,
+		"4k3/8/8/8/8/8/4P3/4K3 w - - 0 1":  chess.Ongoing,
+	} {
+		b, err := chess.NewBoardFromFEN(fen)
+		if assert.NoError(t, err, fen) {
+			assert.Equal(t, status, b.Status(), fen)
+		}
+	}
+
+	b, err := chess.NewBoardFromFEN("4k3/8/8/8/8/8/3p4/4K3 w - - 0 1")
+	if assert.NoError(t, err) {
+		assertParse(t, b, "Kxd2")
+		assert.Equal(t, chess.InsufficientMaterial, b.Status())
+		assert.Equal(t, chess.Draw, b.Result())
+		assertMoveError(t, b, "Ke7", "invalid move Ke7: game ended by insufficient material")
+	}
+}
+
+func TestBoardFiftyMoveRule(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewBoardFromFEN("4k3/8/8/8/8/8/8/R3K3 b - - 98 80")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertParse(t, b, "Kd7")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	assertParse(t, b, "Ra2")
+	assert.Equal(t, chess.FiftyMoveRule, b.Status())
+	assert.Equal(t, chess.Draw, b.Result())
+
+	assertMoveError(t, b, "Kd6", "invalid move Kd6: game ended by fifty-move rule")
+}
+
+func TestBoardThreefoldRepetition(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "Nf3 Nf6 Ng1 Ng8 Nf3 Nf6 Ng1")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	// initial position is repeated for the third time
+	assertParse(t, b, "Ng8")
+	assert.Equal(t, chess.ThreefoldRepetition, b.Status())
+	assert.Equal(t, chess.Draw, b.Result())
+
+	assertMoveError(t, b, "Nf3", "invalid move Nf3: game ended by threefold repetition")
+
+	// castling rights are part of the position
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Ke2 Ke7 Ke1 Ke8 Ke2 Ke7 Ke1 Ke8")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	assertParse(t, b, "Ke2 Ke7")
+	assert.Equal(t, chess.ThreefoldRepetition, b.Status())
 }
 
 func TestBoardPin(t *testing.T) {
@@ -495,7 +626,7 @@ func TestBoardPin(t *testing.T) {
 
 	assertParse(t, b, "d4 e5 Nc3 Bb4")
 
-	assertMoveError(t, b, "Ne4", "invalid move Ne4: king is in check")
+	assertMoveError(t, b, "Ne4", "invalid move Ne4: knight on c3 is pinned to the king by black bishop on b4")
 }
 
 func TestBoardCastle(t *testing.T) {
@@ -527,6 +658,45 @@ func TestBoardCastle(t *testing.T) {
 	assertNoPiece(t, b, "e1")
 }
 
+func TestBoardCastleInvalid(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	// king moved
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Be2 Be7 Kf1 Kf8 Ke1 Ke8")
+
+	assertMoveError(t, b, "O-O", "invalid move O-O: no castling rights")
+
+	// rook moved
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Be2 Be7 Rg1 Rg8 Rh1 Rh8")
+
+	assertMoveError(t, b, "O-O", "invalid move O-O: no castling rights")
+
+	// rook captured
+	b = chess.NewBoard()
+
+	assertParse(t, b, "g4 b6 Bh3 Bb7 Nf3 Bxf3 e3 Bxh1 Bg2 Bxg2")
+
+	assertMoveError(t, b, "O-O", "invalid move O-O: no castling rights")
+
+	// king in check
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Bc4 Bc5 d3 Bb4+")
+
+	assertMoveError(t, b, "O-O", "invalid move O-O: king is in check")
+
+	// king passes attacked square
+	b = chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Bc4 Bc5 d3 d6 Nc3 Bg4 Be3 Nc6 Qd2 Qd7 h3 Bxf3")
+
+	assertMoveError(t, b, "O-O-O", "invalid move O-O-O: king passes attacked square d1")
+}
+
 func TestBoardParseAlgebraicNotation(t *testing.T) {
 	t.Parallel()
 
@@ -572,5 +742,6 @@ func assertNoPiece(t *testing.T, b *chess.Board, position string) {
 }
 
 func assertMoveError(t *testing.T, b *chess.Board, position string, message string) {
-	assert.ErrorContains(t, b.Move(position), message)
+	_, err := b.Move(position)
+	assert.ErrorContains(t, err, message)
 }
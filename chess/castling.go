@@ -0,0 +1,267 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+type castlingRights uint8
+
+const (
+	whiteKingSide castlingRights = 1 << iota
+	whiteQueenSide
+	blackKingSide
+	blackQueenSide
+
+	allCastlingRights = whiteKingSide | whiteQueenSide | blackKingSide | blackQueenSide
+)
+
+// castleFiles are the initial files of the king and the rooks that can castle.
+// They only differ from the e-, h- and a-file in Chess960.
+type castleFiles struct {
+	king      int
+	kingRook  int
+	queenRook int
+}
+
+var standardCastleFiles = castleFiles{king: 4, kingRook: 7, queenRook: 0}
+
+func (f castleFiles) rook(side CastleSide) int {
+	if side == QueenSide {
+		return f.queenRook
+	}
+	return f.kingRook
+}
+
+// castlingRight returns the right of the given side to castle to the given side of the board.
+func castlingRight(s side, side CastleSide) castlingRights {
+	right := whiteKingSide
+	if side == QueenSide {
+		right = whiteQueenSide
+	}
+	if s == black {
+		right <<= 2
+	}
+	return right
+}
+
+// castleTargets returns the files the king and the rook end up on after castling.
+// They are the same in standard chess and Chess960.
+func castleTargets(side CastleSide) (king int, rook int) {
+	if side == QueenSide {
+		return 2, 3
+	}
+	return 6, 5
+}
+
+// backRank returns the row of the initial squares of the pieces.
+func backRank(s side) int {
+	if s == white {
+		return 7
+	}
+	return 0
+}
+
+// updateCastlingRights revokes castling rights if a king or rook left its initial square.
+// Since rights are never restored, this also covers kings or rooks that moved back and captured rooks.
+func (b *Board) updateCastlingRights() {
+	for _, s := range []side{white, black} {
+		var (
+			y     = backRank(s)
+			files = b.castleFiles[s]
+		)
+
+		if !b.isPiece(files.king, y, King, s.color()) {
+			b.castling &^= castlingRight(s, KingSide) | castlingRight(s, QueenSide)
+		}
+		if !b.isPiece(files.kingRook, y, Rook, s.color()) {
+			b.castling &^= castlingRight(s, KingSide)
+		}
+		if !b.isPiece(files.queenRook, y, Rook, s.color()) {
+			b.castling &^= castlingRight(s, QueenSide)
+		}
+	}
+}
+
+// checkCastle returns an error if the player whose turn it is can't castle to the given side.
+func (b *Board) checkCastle(side CastleSide) error {
+	var (
+		us             = b.us()
+		y              = backRank(us)
+		kingX          = b.castleFiles[us].king
+		rookX          = b.castleFiles[us].rook(side)
+		kingTo, rookTo = castleTargets(side)
+	)
+
+	if b.castling&castlingRight(us, side) == 0 || !b.isPiece(kingX, y, King, b.turn) || !b.isPiece(rookX, y, Rook, b.turn) {
+		return errors.New("no castling rights")
+	}
+
+	// all squares the king and the rook pass or land on must be empty except for the king and the rook themselves.
+	// in standard chess, these are the squares between them.
+	var between []int
+	for _, path := range [][2]int{{kingX, kingTo}, {rookX, rookTo}} {
+		for x := min(path[0], path[1]); x <= max(path[0], path[1]); x++ {
+			if x != kingX && x != rookX && !slices.Contains(between, x) {
+				between = append(between, x)
+			}
+		}
+	}
+	// report the blocked square closest to the king
+	slices.SortFunc(between, func(a, b int) int {
+		return max(a-kingX, kingX-a) - max(b-kingX, kingX-b)
+	})
+
+	for _, x := range between {
+		if b.occupancy()&bit(x, y) != 0 {
+			return fmt.Errorf("%s is blocked", Square{X: x, Y: y})
+		}
+	}
+
+	if b.InCheck() {
+		return errors.New("king is in check")
+	}
+
+	// the king must not pass over an attacked square.
+	// the destination square is checked like for any other king move.
+	for x := min(kingX, kingTo) + 1; x < max(kingX, kingTo); x++ {
+		pass := Square{X: x, Y: y}
+		if b.attacked(pass.index(), us.other()) {
+			return fmt.Errorf("king passes attacked square %s", pass)
+		}
+	}
+
+	return nil
+}
+
+func (b *Board) isPiece(x int, y int, name PieceName, color Color) bool {
+	return b.pieces[sideOf(color)][pieceIndex(name)]&bit(x, y) != 0
+}
+
+// castleTo returns the target square of castling moves of the player whose turn it is.
+// In Chess960, the king moves onto the rook like in UCI since the king might already be on its destination.
+func (b *Board) castleTo(side CastleSide) Square {
+	us := b.us()
+	if b.chess960 {
+		return Square{X: b.castleFiles[us].rook(side), Y: backRank(us)}
+	}
+	kingTo, _ := castleTargets(side)
+	return Square{X: kingTo, Y: backRank(us)}
+}
+
+// parseCastling reads castling rights in FEN, X-FEN or Shredder-FEN.
+// Files like "HAha" name the castling rooks of Chess960 positions.
+// KQkq name the outermost rooks on the king side and the queen side of the king like in X-FEN.
+func (b *Board) parseCastling(field string) error {
+	b.castleFiles = [2]castleFiles{standardCastleFiles, standardCastleFiles}
+
+	if field == "-" {
+		return nil
+	}
+
+	for _, r := range field {
+		s := white
+		if unicode.IsLower(r) {
+			s = black
+			r = unicode.ToUpper(r)
+		}
+
+		var (
+			y     = backRank(s)
+			files = &b.castleFiles[s]
+			kingX = -1
+			rookX = -1
+			side  CastleSide
+		)
+
+		for x := 0; x < 8; x++ {
+			if b.isPiece(x, y, King, s.color()) {
+				kingX = x
+			}
+		}
+
+		switch {
+		case r == 'K':
+			side = KingSide
+			for x := 7; x > kingX; x-- {
+				if b.isPiece(x, y, Rook, s.color()) {
+					rookX = x
+					break
+				}
+			}
+		case r == 'Q':
+			side = QueenSide
+			for x := 0; x < kingX; x++ {
+				if b.isPiece(x, y, Rook, s.color()) {
+					rookX = x
+					break
+				}
+			}
+		case r >= 'A' && r <= 'H':
+			rookX = int(r - 'A')
+			side = KingSide
+			if rookX < kingX {
+				side = QueenSide
+			}
+			b.chess960 = true
+		default:
+			return fmt.Errorf("invalid castling rights: %s", field)
+		}
+
+		if kingX == -1 || rookX == -1 || rookX == kingX {
+			// rights without a king and a rook on the back rank are ignored
+			continue
+		}
+
+		files.king = kingX
+		if side == KingSide {
+			files.kingRook = rookX
+		} else {
+			files.queenRook = rookX
+		}
+		b.castling |= castlingRight(s, side)
+
+		if *files != standardCastleFiles {
+			b.chess960 = true
+		}
+	}
+
+	return nil
+}
+
+// castlingField returns the castling rights for FEN.
+// Chess960 positions use Shredder-FEN with the files of the castling rooks.
+func (b *Board) castlingField() string {
+	var sb strings.Builder
+
+	for _, s := range []side{white, black} {
+		for _, side := range []CastleSide{KingSide, QueenSide} {
+			if b.castling&castlingRight(s, side) == 0 {
+				continue
+			}
+
+			var r rune
+			switch {
+			case b.chess960:
+				r = rune('A' + b.castleFiles[s].rook(side))
+			case side == KingSide:
+				r = 'K'
+			default:
+				r = 'Q'
+			}
+
+			if s == black {
+				r = unicode.ToLower(r)
+			}
+			sb.WriteRune(r)
+		}
+	}
+
+	if sb.Len() == 0 {
+		return "-"
+	}
+	return sb.String()
+}
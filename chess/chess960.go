@@ -0,0 +1,106 @@
+package chess
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Chess960Positions is the number of start positions in Chess960.
+const Chess960Positions = 960
+
+// knight placements on the five squares left after placing bishops and queen
+var chess960Knights = [10][2]int{
+	{0, 1}, {0, 2}, {0, 3}, {0, 4}, {1, 2},
+	{1, 3}, {1, 4}, {2, 3}, {2, 4}, {3, 4},
+}
+
+// Chess960BackRank returns the pieces on the back rank of the Chess960 start position with the given number
+// like "RNBQKBNR" for position 518. Positions are numbered from 0 to 959 as proposed by Scharnagl.
+func Chess960BackRank(n int) (string, error) {
+	if n < 0 || n >= Chess960Positions {
+		return "", fmt.Errorf("invalid Chess960 position: %d is not between 0 and %d", n, Chess960Positions-1)
+	}
+
+	var rank [8]byte
+
+	// bishops on light and dark squares
+	rank[n%4*2+1] = 'B'
+	n /= 4
+	rank[n%4*2] = 'B'
+	n /= 4
+
+	// empty returns the files that are still empty
+	empty := func() []int {
+		var files []int
+		for x, p := range rank {
+			if p == 0 {
+				files = append(files, x)
+			}
+		}
+		return files
+	}
+
+	rank[empty()[n%6]] = 'Q'
+	n /= 6
+
+	files := empty()
+	for _, i := range chess960Knights[n] {
+		rank[files[i]] = 'N'
+	}
+
+	// king is always between the rooks
+	for i, x := range empty() {
+		rank[x] = "RKR"[i]
+	}
+
+	return string(rank[:]), nil
+}
+
+// NewChess960Board creates a board with the Chess960 start position with the given number.
+func NewChess960Board(n int) (*Board, error) {
+	rank, err := Chess960BackRank(n)
+	if err != nil {
+		return nil, err
+	}
+	return NewBoardWithBackRank(rank)
+}
+
+// NewBoardWithBackRank creates a Chess960 board where the pieces start on the given back rank like "RNBQKBNR".
+// The bishops must be on squares of different colors and the king must be between the rooks.
+func NewBoardWithBackRank(rank string) (*Board, error) {
+	if len(rank) != 8 {
+		return nil, parseError("back rank", "expected 8 pieces but got %d", len(rank))
+	}
+
+	for _, r := range "RNBQK" {
+		if strings.Count(rank, string(r)) != strings.Count("RNBQKBNR", string(r)) {
+			return nil, parseError("back rank", "expected a king, a queen and two rooks, bishops and knights in %s", rank)
+		}
+	}
+
+	var (
+		bishop1 = strings.Index(rank, "B")
+		bishop2 = strings.LastIndex(rank, "B")
+		rook1   = strings.Index(rank, "R")
+		rook2   = strings.LastIndex(rank, "R")
+		king    = strings.Index(rank, "K")
+	)
+
+	if bishop1%2 == bishop2%2 {
+		return nil, parseError("back rank", "bishops of %s are on squares of the same color", rank)
+	}
+
+	if king < rook1 || king > rook2 {
+		return nil, parseError("back rank", "king of %s is not between the rooks", rank)
+	}
+
+	castling := fmt.Sprintf("%c%c", 'A'+rook2, 'A'+rook1)
+	fen := fmt.Sprintf("%s/pppppppp/8/8/8/8/PPPPPPPP/%s w %s%s - 0 1", strings.ToLower(rank), rank, castling, strings.ToLower(castling))
+
+	return newBoardFromFEN(fen, true)
+}
+
+// Chess960 returns true if castling follows the Chess960 rules.
+func (b *Board) Chess960() bool {
+	return b.chess960
+}
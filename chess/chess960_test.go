@@ -0,0 +1,181 @@
+package chess_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestChess960BackRank(t *testing.T) {
+	t.Parallel()
+
+	for n, expected := range map[int]string{
+		0:   "BBQNNRKR",
+		518: "RNBQKBNR",
+		959: "RKRNNQBB",
+	} {
+		rank, err := chess.Chess960BackRank(n)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, rank, "position %d", n)
+	}
+
+	// all positions are different and valid
+	ranks := make(map[string]bool)
+	for n := 0; n < chess.Chess960Positions; n++ {
+		rank, err := chess.Chess960BackRank(n)
+		if !assert.NoError(t, err) {
+			return
+		}
+		ranks[rank] = true
+
+		_, err = chess.NewBoardWithBackRank(rank)
+		assert.NoError(t, err, "position %d", n)
+	}
+	assert.Len(t, ranks, chess.Chess960Positions)
+
+	_, err := chess.Chess960BackRank(960)
+	assert.ErrorContains(t, err, "invalid Chess960 position: 960 is not between 0 and 959")
+	_, err = chess.Chess960BackRank(-1)
+	assert.Error(t, err)
+}
+
+func TestNewBoardWithBackRank(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewBoardWithBackRank("BBQNNRKR")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, b.Chess960())
+	assert.Equal(t, "bbqnnrkr/pppppppp/8/8/8/8/PPPPPPPP/BBQNNRKR w HFhf - 0 1", b.FEN())
+	assert.Len(t, b.LegalMoves(), 20)
+
+	b, err = chess.NewChess960Board(518)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w HAha - 0 1", b.FEN())
+
+	for rank, message := range map[string]string{
+		"RNBQKBN":  "invalid back rank: expected 8 pieces but got 7",
+		"RNBQKBNN": "invalid back rank: expected a king, a queen and two rooks, bishops and knights in RNBQKBNN",
+		"RNBQKNBR": "invalid back rank: bishops of RNBQKNBR are on squares of the same color",
+		"KRBQNBNR": "invalid back rank: king of KRBQNBNR is not between the rooks",
+	} {
+		_, err := chess.NewBoardWithBackRank(rank)
+		assert.EqualError(t, err, message)
+	}
+}
+
+func TestChess960Castle(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewChess960Board(0)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	// the king is already on g1 but the other rook is on f1
+	assertMoveError(t, b, "O-O", "invalid move O-O: f1 is blocked")
+
+	assertParse(t, b, "e4 e5 Nf3 Nf6")
+	assertMoveError(t, b, "O-O-O", "invalid move O-O-O: d1 is blocked")
+
+	// in Chess960, castling moves the king onto the rook in UCI
+	b, err = chess.NewBoardFromFEN("1r4k1/8/8/8/8/8/8/1R4KR w HBb - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, b.Chess960())
+
+	m := assertLegalMove(t, b.LegalMoves(), "g1", "h1")
+	assert.Equal(t, chess.KingSide, m.Castle)
+	assert.Equal(t, "g1h1", m.UCI())
+
+	// the king stays on g1 and only the rook moves
+	m, err = b.Move("O-O")
+	assert.NoError(t, err)
+	assert.Equal(t, "O-O", m.SAN)
+	assert.Equal(t, "1r4k1/8/8/8/8/8/8/1R3RK1 b Bb - 1 1", b.FEN())
+
+	// the king would pass f8 which the rook attacks now
+	assertMoveError(t, b, "O-O-O", "invalid move O-O-O: king passes attacked square f8")
+
+	// the king must not pass attacked squares
+	b, err = chess.NewBoardFromFEN("3rk3/8/8/8/8/8/8/1R2K3 w B - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertMoveError(t, b, "O-O-O", "invalid move O-O-O: king passes attacked square d1")
+
+	// the king passes c1 to d1 and the rook stays on b1
+	b, err = chess.NewBoardFromFEN("4k3/8/8/8/8/8/8/1R2K3 w B - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = b.Move("e1b1")
+	assert.NoError(t, err)
+	assertPiece(t, b, "c1", chess.King, chess.Light)
+	assertPiece(t, b, "d1", chess.Rook, chess.Light)
+	assert.Equal(t, "4k3/8/8/8/8/8/8/2KR4 b - - 1 1", b.FEN())
+}
+
+func TestChess960FEN(t *testing.T) {
+	t.Parallel()
+
+	// X-FEN names the outermost rooks with KQkq
+	b, err := chess.NewBoardFromFEN("rk2r3/8/8/8/8/8/8/RK2R3 w KQkq - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, b.Chess960())
+	assert.Equal(t, "rk2r3/8/8/8/8/8/8/RK2R3 w EAea - 0 1", b.FEN())
+
+	// the king is on the e-file but the rooks are not on the a- and h-file
+	b, err = chess.NewBoardFromFEN("brnnkrqb/pppppppp/8/8/8/8/PPPPPPPP/BRNNKRQB w KQkq - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, b.Chess960())
+	assert.Equal(t, "brnnkrqb/pppppppp/8/8/8/8/PPPPPPPP/BRNNKRQB w FBfb - 0 1", b.FEN())
+
+	pgn := "[Variant \"Chess960\"]\n[SetUp \"1\"]\n[FEN \"brnnkrqb/pppppppp/8/8/8/8/PPPPPPPP/BRNNKRQB w KQkq - 0 1\"]\n\n" +
+		"1. g3 g6 2. Qg2 Qg7 3. O-O O-O *"
+	b, err = chess.NewGameFromPGN(pgn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertPiece(t, b, "g1", chess.King, chess.Light)
+	assertPiece(t, b, "f1", chess.Rook, chess.Light)
+
+	// standard positions stay standard
+	b, err = chess.NewBoardFromFEN(chess.StartFEN)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.False(t, b.Chess960())
+	assert.Equal(t, chess.StartFEN, b.FEN())
+}
+
+func TestChess960PGN(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewChess960Board(518)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Bc4 Bc5 O-O")
+
+	pgn := b.PGN(nil)
+	assert.Contains(t, pgn, `[Variant "Chess960"]`+"\n"+`[SetUp "1"]`+"\n"+`[FEN "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w HAha - 0 1"]`)
+	assert.True(t, strings.HasSuffix(pgn, "1. e4 e5 2. Nf3 Nf6 3. Bc4 Bc5 4. O-O *\n"))
+
+	replay, err := chess.NewGameFromPGN(pgn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, replay.Chess960())
+	assert.Equal(t, b.FEN(), replay.FEN())
+}
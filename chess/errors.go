@@ -0,0 +1,105 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	// ErrGameOver is matched by all errors for moves after the game ended.
+	ErrGameOver     = errors.New("game over")
+	ErrNoMoveToUndo = errors.New("no move to undo")
+)
+
+// ParseError is returned for text that is not valid notation.
+type ParseError struct {
+	// notation like "move", "PGN" or "FEN"
+	Notation string
+	Reason   string
+	// legal moves that are closest to invalid moves
+	Suggestions []Move
+}
+
+func (e *ParseError) Error() string {
+	msg := fmt.Sprintf("invalid %s: %s", e.Notation, e.Reason)
+	if len(e.Suggestions) > 0 {
+		msg = fmt.Sprintf("%s. Did you mean %s?", msg, joinMoves(e.Suggestions))
+	}
+	return msg
+}
+
+func parseError(notation string, format string, args ...any) error {
+	return &ParseError{Notation: notation, Reason: fmt.Sprintf(format, args...)}
+}
+
+// IllegalMoveError is returned for moves that break the rules in the current position.
+type IllegalMoveError struct {
+	Move   string
+	Reason string
+	// legal moves that are closest to Move
+	Suggestions []Move
+}
+
+func (e *IllegalMoveError) Error() string {
+	msg := fmt.Sprintf("invalid move %s: %s", e.Move, e.Reason)
+	if len(e.Suggestions) > 0 {
+		msg = fmt.Sprintf("%s. Did you mean %s?", msg, joinMoves(e.Suggestions))
+	}
+	return msg
+}
+
+func illegalMove(move string, format string, args ...any) error {
+	return &IllegalMoveError{Move: move, Reason: fmt.Sprintf(format, args...)}
+}
+
+// AmbiguousMoveError is returned for moves that more than one piece can make.
+type AmbiguousMoveError struct {
+	Move string
+	// legal moves that match Move, with disambiguation in SAN
+	Candidates []Move
+}
+
+func (e *AmbiguousMoveError) Error() string {
+	var (
+		c    = e.Candidates[0]
+		from = make([]string, len(e.Candidates))
+	)
+
+	for i, m := range e.Candidates {
+		from[i] = m.From.String()
+	}
+
+	return fmt.Sprintf(
+		"move ambiguous: %d %ss can move to %s from %s and %s. Did you mean %s?",
+		len(e.Candidates), pieceNames[c.Piece], c.To, strings.Join(from[:len(from)-1], ", "), from[len(from)-1],
+		joinMoves(e.Candidates),
+	)
+}
+
+// GameOverError is returned for moves after the game ended.
+type GameOverError struct {
+	Move   string
+	Status Status
+}
+
+func (e *GameOverError) Error() string {
+	return fmt.Sprintf("invalid move %s: game ended by %s", e.Move, e.Status)
+}
+
+func (e *GameOverError) Is(target error) bool {
+	return target == ErrGameOver
+}
+
+// joinMoves lists the moves in SAN like "Nf3, Nc3 or Nh3".
+func joinMoves(moves []Move) string {
+	sans := make([]string, len(moves))
+	for i, m := range moves {
+		sans[i] = m.SAN
+	}
+
+	if len(sans) == 1 {
+		return sans[0]
+	}
+	return fmt.Sprintf("%s or %s", strings.Join(sans[:len(sans)-1], ", "), sans[len(sans)-1])
+}
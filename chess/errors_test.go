@@ -0,0 +1,77 @@
+package chess_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardMoveErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	var (
+		illegal   *chess.IllegalMoveError
+		ambiguous *chess.AmbiguousMoveError
+		parse     *chess.ParseError
+		gameOver  *chess.GameOverError
+	)
+
+	b := chess.NewBoard()
+
+	_, err := b.Move("Bc4")
+	if assert.ErrorAs(t, err, &illegal) {
+		assert.Equal(t, "Bc4", illegal.Move)
+		assert.Equal(t, "no bishop found that can move to c4: bishop on f1 is blocked by white pawn on e2", illegal.Reason)
+	}
+
+	_, err = b.Move("O-O")
+	if assert.ErrorAs(t, err, &illegal) {
+		assert.Equal(t, "f1 is blocked", illegal.Reason)
+	}
+
+	_, err = b.Move("Qz9")
+	if assert.ErrorAs(t, err, &parse) {
+		assert.Equal(t, "move", parse.Notation)
+	}
+
+	assertParse(t, b, "e4 e5 Nf3 d6 Nc3 d5 Nb5 d4")
+	_, err = b.Move("Nxd4")
+	if assert.ErrorAs(t, err, &ambiguous) {
+		var candidates []string
+		for _, m := range ambiguous.Candidates {
+			candidates = append(candidates, m.SAN)
+		}
+		assert.Equal(t, []string{"Nbxd4", "Nfxd4"}, candidates)
+	}
+	assert.False(t, errors.As(err, &illegal))
+
+	b = chess.NewBoard()
+	assertParse(t, b, "f3 e5 g4 Qh4")
+	_, err = b.Move("a3")
+	assert.ErrorIs(t, err, chess.ErrGameOver)
+	if assert.ErrorAs(t, err, &gameOver) {
+		assert.Equal(t, chess.Checkmate, gameOver.Status)
+	}
+
+	err = chess.NewBoard().Undo()
+	assert.ErrorIs(t, err, chess.ErrNoMoveToUndo)
+}
+
+func TestParseErrorKinds(t *testing.T) {
+	t.Parallel()
+
+	var parse *chess.ParseError
+
+	_, err := chess.NewBoardFromFEN("8/8/8 w - - 0 1")
+	if assert.ErrorAs(t, err, &parse) {
+		assert.Equal(t, "FEN", parse.Notation)
+	}
+
+	_, err = chess.ParsePGN("1. e4 (e5")
+	if assert.ErrorAs(t, err, &parse) {
+		assert.Equal(t, "PGN", parse.Notation)
+		assert.Equal(t, "unterminated variation", parse.Reason)
+	}
+}
@@ -0,0 +1,229 @@
+package chess
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// moves that start like long algebraic coordinates such as "g1f3" or "g1-"
+var coordinatePrefixRegexp = regexp.MustCompile(`^[a-h][1-8][-x]?[a-h]`)
+
+const (
+	// legal moves that differ from the input in more characters are not suggested
+	maxSuggestionDistance = 2
+	maxSuggestions        = 3
+)
+
+// Suggestions returns the legal moves that are closest to the given move.
+// This catches typos, wrong capture marks and missing disambiguation.
+// If the move names a piece, only moves of that piece are suggested.
+func (b *Board) Suggestions(move string) []Move {
+	var (
+		input       = normalizeMove(move)
+		piece       = movedPiece(move)
+		suggestions []Move
+		best        = maxSuggestionDistance
+	)
+
+	for _, m := range b.LegalMoves() {
+		m.SAN = b.SAN(m)
+
+		if piece != "" && m.Piece != piece {
+			continue
+		}
+
+		d := min(editDistance(input, normalizeMove(m.SAN)), editDistance(input, m.UCI()))
+
+		if d > best {
+			continue
+		}
+		if d < best {
+			best = d
+			suggestions = nil
+		}
+		if d == best {
+			suggestions = append(suggestions, m)
+		}
+	}
+
+	slices.SortFunc(suggestions, func(a, b Move) int {
+		return strings.Compare(a.SAN, b.SAN)
+	})
+
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+
+	return suggestions
+}
+
+// withSuggestions adds the legal moves that are closest to the given move to illegal and invalid move errors.
+func (b *Board) withSuggestions(err error, move string) error {
+	var (
+		illegal *IllegalMoveError
+		parse   *ParseError
+	)
+	if errors.As(err, &illegal) {
+		illegal.Suggestions = b.Suggestions(move)
+	} else if errors.As(err, &parse) && parse.Notation == "move" {
+		parse.Suggestions = b.Suggestions(move)
+	}
+	return err
+}
+
+// checkReason explains why the move would leave the own king in check.
+func (b *Board) checkReason(m Move) string {
+	if b.InCheck() {
+		return "king is in check"
+	}
+
+	if m.Castle != NoCastle {
+		kingTo, _ := castleTargets(m.Castle)
+		return fmt.Sprintf("king would be in check on %s", Square{X: kingTo, Y: m.From.Y})
+	}
+
+	if m.Piece == King {
+		return fmt.Sprintf("king would be in check on %s", m.To)
+	}
+
+	tmp := *b
+	tmp.applyMove(m)
+
+	us := b.us()
+	king := tmp.pieces[us][kingIndex].lowest()
+	if attackers := tmp.attackers(king, us.other()); attackers != 0 {
+		sq := squareOf(attackers.lowest())
+		return fmt.Sprintf("%s on %s is pinned to the king by %s on %s", pieceNames[m.Piece], m.From, tmp.pieceOn(sq.index()), sq)
+	}
+
+	return "king would be in check"
+}
+
+// blockedReason explains which piece blocks the path of a piece with the given name to the given square.
+// It returns an empty string if no piece with that name could reach the square on an empty board.
+func (b *Board) blockedReason(name PieceName, to Square) string {
+	var (
+		us       = b.us()
+		piece    = pieceIndex(name)
+		target   = to.index()
+		occupied = b.occupancy()
+	)
+
+	if piece == -1 {
+		return ""
+	}
+
+	for bb := b.pieces[us][piece]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+
+		for _, d := range pathDirections(name, us) {
+			ray := rays[d][from]
+			if ray&(1<<target) == 0 {
+				continue
+			}
+
+			path := ray &^ rays[d][target]
+			if name != Pawn {
+				// the target itself can be captured by all pieces but pawns moving forward
+				path &^= 1 << target
+			} else if dist := max(from/8-target/8, target/8-from/8); dist > 2 || (dist == 2 && from/8 != pawnStartRank(us)) {
+				continue
+			}
+
+			blockers := path & occupied
+			if blockers == 0 {
+				continue
+			}
+
+			sq := squareOf(nearestBlocker(blockers, d))
+			return fmt.Sprintf("%s on %s is blocked by %s on %s", pieceNames[name], squareOf(from), b.pieceOn(sq.index()), sq)
+		}
+	}
+
+	return ""
+}
+
+// pathDirections returns the rays along which the piece moves.
+func pathDirections(name PieceName, s side) []int {
+	switch name {
+	case Bishop:
+		return bishopRays
+	case Rook:
+		return rookRays
+	case Queen:
+		return append(slices.Clone(bishopRays), rookRays...)
+	case Pawn:
+		// light pawns move towards y = 0
+		if s == white {
+			return []int{5}
+		}
+		return []int{1}
+	default:
+		// knights and kings jump
+		return nil
+	}
+}
+
+// pawnStartRank returns the row of the initial squares of the pawns.
+func pawnStartRank(s side) int {
+	if s == white {
+		return 6
+	}
+	return 1
+}
+
+// movedPiece returns the piece the move names or an empty name if it's unclear.
+func movedPiece(move string) PieceName {
+	if move == "" || coordinatePrefixRegexp.MatchString(move) {
+		// coordinates name squares instead of pieces
+		return ""
+	}
+
+	switch c := move[0]; {
+	case c == 'b':
+		// lowercase b is the file of a pawn or a bishop
+		return ""
+	case c >= 'a' && c <= 'h':
+		return Pawn
+	case c == 'O' || c == '0':
+		return King
+	}
+
+	name := PieceName(strings.ToLower(move[:1]))
+	if _, ok := pieceNames[name]; !ok {
+		return ""
+	}
+	return name
+}
+
+// normalizeMove removes check marks and case from a move for comparison.
+func normalizeMove(move string) string {
+	return strings.ToLower(strings.TrimRight(strings.TrimSpace(move), "+#!?"))
+}
+
+// editDistance returns the Levenshtein distance between the given strings.
+func editDistance(a string, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(b)]
+}
@@ -0,0 +1,72 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardSuggestions(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+	assertParse(t, b, "e4 e5 Nf3 d6 Nc3 d5 Nb5 d4")
+
+	for move, expected := range map[string][]string{
+		// typos
+		"Nf5": {"Ng5"},
+		"Ke3": {"Ke2"},
+		"Bc5": {"Bc4"},
+		// missing disambiguation
+		"Nxd4": {"Nbxd4", "Nfxd4"},
+		// only moves of the named piece
+		"Qd3": {"Qe2"},
+		// coordinates
+		"e1e3": {"Ke2"},
+		// too different
+		"Zz9": nil,
+	} {
+		var suggestions []string
+		for _, m := range b.Suggestions(move) {
+			suggestions = append(suggestions, m.SAN)
+		}
+		assert.Equal(t, expected, suggestions, move)
+	}
+}
+
+func TestBoardMoveErrorExplanations(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	// blocked paths
+	assertMoveError(t, b, "Bc4", "no bishop found that can move to c4: bishop on f1 is blocked by white pawn on e2")
+	assertMoveError(t, b, "Qd3", "no queen found that can move to d3: queen on d1 is blocked by white pawn on d2")
+	assertMoveError(t, b, "Ra3", "no rook found that can move to a3: rook on a1 is blocked by white pawn on a2")
+
+	// pawns can't capture forward
+	assertParse(t, b, "e4 e5")
+	assertMoveError(t, b, "e5", "no pawn found that can move to e5: pawn on e4 is blocked by black pawn on e5")
+
+	// pinned pieces
+	b = chess.NewBoard()
+	assertParse(t, b, "e4 d5 Nc3 d4 a3 Qd6 Nd5 Qb4")
+	assertMoveError(t, b, "d3", "invalid move d3: pawn on d2 is pinned to the king by black queen on b4")
+	assertMoveError(t, b, "d2d3", "invalid move d2d3: pawn on d2 is pinned to the king by black queen on b4")
+
+	// king moves into check
+	b = chess.NewBoard()
+	assertParse(t, b, "e4 e5 Ke2 Bc5")
+	assertMoveError(t, b, "Ke3", "invalid move Ke3: king would be in check on e3")
+
+	// missing disambiguation
+	b = chess.NewBoard()
+	assertParse(t, b, "e4 e5 Nf3 d6 Nc3 d5 Nb5 d4")
+	assertMoveError(t, b, "Nxd4", "move ambiguous: 2 knights can move to d4 from b5 and f3. Did you mean Nbxd4 or Nfxd4?")
+
+	// typos that are no valid notation
+	b = chess.NewBoard()
+	assertMoveError(t, b, "Nf9", "invalid move: Nf9: square does not exist: f9. Did you mean Nf3?")
+	assertMoveError(t, b, "Mf3", "invalid move: Mf3. Did you mean Nf3 or f3?")
+}
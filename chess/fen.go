@@ -0,0 +1,207 @@
+package chess
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const StartFEN = "rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1"
+
+// NewBoardFromFEN creates a board from a position in Forsyth-Edwards Notation.
+// The halfmove clock and fullmove number are optional and default to 0 and 1.
+// Castling rights in X-FEN or Shredder-FEN start a Chess960 game.
+func NewBoardFromFEN(fen string) (*Board, error) {
+	return newBoardFromFEN(fen, false)
+}
+
+func newBoardFromFEN(fen string, chess960 bool) (*Board, error) {
+	var (
+		board  = &Board{chess960: chess960}
+		fields = strings.Fields(fen)
+		err    error
+	)
+
+	if len(fields) == 4 {
+		fields = append(fields, "0", "1")
+	}
+
+	if len(fields) != 6 {
+		return nil, parseError("FEN", "expected 6 fields but got %d", len(fields))
+	}
+
+	if err = board.parsePlacement(fields[0]); err != nil {
+		return nil, parseError("FEN", "%v", err)
+	}
+
+	switch fields[1] {
+	case "w":
+		board.turn = Light
+	case "b":
+		board.turn = Dark
+	default:
+		return nil, parseError("FEN", "invalid side to move: %s", fields[1])
+	}
+
+	if err = board.parseCastling(fields[2]); err != nil {
+		return nil, parseError("FEN", "%v", err)
+	}
+	// ignore rights that don't match the position
+	board.updateCastlingRights()
+
+	if fields[3] != "-" {
+		var x, y int
+		if x, y, err = getXY(fields[3]); err != nil {
+			return nil, parseError("FEN", "invalid en passant square: %v", err)
+		}
+		if (board.turn == Light && y != 2) || (board.turn == Dark && y != 5) {
+			return nil, parseError("FEN", "invalid en passant square: %s", fields[3])
+		}
+		board.enPassant = &Square{X: x, Y: y}
+	}
+
+	if board.halfmoveClock, err = strconv.Atoi(fields[4]); err != nil || board.halfmoveClock < 0 {
+		return nil, parseError("FEN", "invalid halfmove clock: %s", fields[4])
+	}
+
+	if board.fullmoveNumber, err = strconv.Atoi(fields[5]); err != nil || board.fullmoveNumber < 1 {
+		return nil, parseError("FEN", "invalid fullmove number: %s", fields[5])
+	}
+
+	if err = board.validatePosition(); err != nil {
+		return nil, parseError("FEN", "%v", err)
+	}
+
+	// remember custom start position for PGN export
+	if fen = board.FEN(); fen != StartFEN {
+		board.startFEN = fen
+	}
+
+	board.hash = board.computeHash()
+	board.recordPosition()
+
+	return board, nil
+}
+
+func (b *Board) parsePlacement(placement string) error {
+	ranks := strings.Split(placement, "/")
+
+	if len(ranks) != 8 {
+		return fmt.Errorf("expected 8 ranks but got %d", len(ranks))
+	}
+
+	for y, rank := range ranks {
+		x := 0
+		for _, r := range rank {
+			if r >= '1' && r <= '8' {
+				x += int(r - '0')
+				continue
+			}
+
+			if x >= 8 {
+				return fmt.Errorf("too many squares in rank %d", 8-y)
+			}
+
+			s := black
+			if unicode.IsUpper(r) {
+				s = white
+			}
+
+			piece := pieceIndex(PieceName(unicode.ToLower(r)))
+			if piece == -1 {
+				return fmt.Errorf("invalid piece: %c", r)
+			}
+
+			b.putPiece(y*8+x, piece, s)
+			x++
+		}
+
+		if x != 8 {
+			return fmt.Errorf("expected 8 squares in rank %d but got %d", 8-y, x)
+		}
+	}
+
+	return nil
+}
+
+func (b *Board) validatePosition() error {
+	// pawns can't be on the first or last rank
+	backRanks := bitboard(0xff) | bitboard(0xff)<<56
+	if pawns := (b.pieces[white][pawnIndex] | b.pieces[black][pawnIndex]) & backRanks; pawns != 0 {
+		return fmt.Errorf("pawn on %s", squareOf(pawns.lowest()))
+	}
+
+	if b.pieces[white][kingIndex].count() != 1 || b.pieces[black][kingIndex].count() != 1 {
+		return fmt.Errorf("expected one king per side")
+	}
+
+	// the player who just moved can't be in check
+	them := b.us().other()
+	if b.attacked(b.pieces[them][kingIndex].lowest(), them.other()) {
+		return fmt.Errorf("side not to move is in check")
+	}
+
+	return nil
+}
+
+// InitialFEN returns the position the game started from in Forsyth-Edwards Notation.
+func (b *Board) InitialFEN() string {
+	if b.startFEN != "" {
+		return b.startFEN
+	}
+	return StartFEN
+}
+
+// FEN returns the current position in Forsyth-Edwards Notation.
+func (b *Board) FEN() string {
+	var sb strings.Builder
+
+	for y := 0; y < 8; y++ {
+		empty := 0
+		for x := 0; x < 8; x++ {
+			piece, s := b.pieceAt(y*8 + x)
+			if piece == -1 {
+				empty++
+				continue
+			}
+
+			if empty > 0 {
+				sb.WriteString(strconv.Itoa(empty))
+				empty = 0
+			}
+
+			if s == white {
+				sb.WriteString(strings.ToUpper(string(pieceOrder[piece])))
+			} else {
+				sb.WriteString(string(pieceOrder[piece]))
+			}
+		}
+
+		if empty > 0 {
+			sb.WriteString(strconv.Itoa(empty))
+		}
+
+		if y < 7 {
+			sb.WriteString("/")
+		}
+	}
+
+	if b.turn == Light {
+		sb.WriteString(" w ")
+	} else {
+		sb.WriteString(" b ")
+	}
+
+	sb.WriteString(b.castlingField())
+
+	if b.enPassant != nil {
+		sb.WriteString(" " + b.enPassant.String())
+	} else {
+		sb.WriteString(" -")
+	}
+
+	fmt.Fprintf(&sb, " %d %d", b.halfmoveClock, b.fullmoveNumber)
+
+	return sb.String()
+}
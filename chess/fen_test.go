@@ -0,0 +1,101 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardFEN(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assert.Equal(t, chess.StartFEN, b.FEN())
+
+	assertParse(t, b, "e4")
+	assert.Equal(t, "rnbqkbnr/pppppppp/8/8/4P3/8/PPPP1PPP/RNBQKBNR b KQkq e3 0 1", b.FEN())
+
+	assertParse(t, b, "c5")
+	assert.Equal(t, "rnbqkbnr/pp1ppppp/8/2p5/4P3/8/PPPP1PPP/RNBQKBNR w KQkq c6 0 2", b.FEN())
+
+	assertParse(t, b, "Nf3")
+	assert.Equal(t, "rnbqkbnr/pp1ppppp/8/2p5/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2", b.FEN())
+
+	assertParse(t, b, "Nc6 Be2 Nf6 Kf1")
+	assert.Equal(t, "r1bqkb1r/pp1ppppp/2n2n2/2p5/4P3/5N2/PPPPBPPP/RNBQ1K1R b kq - 5 4", b.FEN())
+}
+
+func TestNewBoardFromFEN(t *testing.T) {
+	t.Parallel()
+
+	fen := "r1bqkb1r/pp1ppppp/2n2n2/2p5/4P3/5N2/PPPPBPPP/RNBQ1K1R b kq - 4 4"
+	b, err := chess.NewBoardFromFEN(fen)
+	if assert.NoError(t, err) {
+		assert.Equal(t, fen, b.FEN())
+
+		assertPiece(t, b, "f1", chess.King, chess.Light)
+		assertPiece(t, b, "c6", chess.Knight, chess.Dark)
+		assertNoPiece(t, b, "e1")
+
+		assertParse(t, b, "e6 d4")
+		assert.Equal(t, "`4...e6 5.d4`", b.AlgebraicNotation())
+		assert.Equal(t, "r1bqkb1r/pp1p1ppp/2n1pn2/2p5/3PP3/5N2/PPP1BPPP/RNBQ1K1R b kq d3 0 5", b.FEN())
+	}
+
+	// clocks are optional
+	b, err = chess.NewBoardFromFEN("4k3/8/8/8/8/8/4P3/4K3 w - -")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1", b.FEN())
+	}
+
+	// castling rights that don't match the position are ignored
+	b, err = chess.NewBoardFromFEN("4k3/8/8/8/8/8/8/R3K3 w KQkq - 0 1")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "4k3/8/8/8/8/8/8/R3K3 w Q - 0 1", b.FEN())
+	}
+}
+
+func TestNewBoardFromFENInvalid(t *testing.T) {
+	t.Parallel()
+
+	for fen, message := range map[string]string{
+		"": "invalid FEN: expected 6 fields but got 0",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP w KQkq - 0 1":           "invalid FEN: expected 8 ranks but got 7",
+		"rnbqkbnr/pppppppp/54/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1": "invalid FEN: expected 8 squares in rank 6 but got 9",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNRR w KQkq - 0 1": "invalid FEN: too many squares in rank 1",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNX w KQkq - 0 1":  "invalid FEN: invalid piece: X",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR x KQkq - 0 1":  "invalid FEN: invalid side to move: x",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQxq - 0 1":  "invalid FEN: invalid castling rights: KQxq",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq e4 0 1": "invalid FEN: invalid en passant square: e4",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - x 1":  "invalid FEN: invalid halfmove clock: x",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 0":  "invalid FEN: invalid fullmove number: 0",
+		"rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQQBNR w KQkq - 0 1":  "invalid FEN: expected one king per side",
+		"4k3/8/8/8/8/8/8/4K2P w - - 0 1":                            "invalid FEN: pawn on h1",
+		"4k3/8/8/8/8/8/8/4K2R w - - 0 1":                            "",
+		"R3k3/8/8/8/8/8/8/4K3 w - - 0 1":                            "invalid FEN: side not to move is in check",
+	} {
+		_, err := chess.NewBoardFromFEN(fen)
+		if message == "" {
+			assert.NoError(t, err, fen)
+		} else {
+			assert.EqualError(t, err, message, fen)
+		}
+	}
+}
+
+func TestBoardInitialFEN(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+	assertParse(t, b, "e4 e5")
+	assert.Equal(t, chess.StartFEN, b.InitialFEN())
+
+	fen := "4k3/8/8/8/8/8/4P3/4K3 w - - 0 1"
+	b, err := chess.NewBoardFromFEN(fen)
+	if assert.NoError(t, err) {
+		assertParse(t, b, "e4")
+		assert.Equal(t, fen, b.InitialFEN())
+	}
+}
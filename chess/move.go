@@ -0,0 +1,378 @@
+package chess
+
+import (
+	"regexp"
+	"slices"
+	"strings"
+)
+
+type CastleSide int
+
+const (
+	NoCastle CastleSide = iota
+	KingSide
+	QueenSide
+)
+
+type Move struct {
+	From      Square
+	To        Square
+	Piece     PieceName
+	Captured  PieceName
+	EnPassant bool
+	Promotion PieceName
+	Castle    CastleSide
+	Check     bool
+	Checkmate bool
+	// SAN is the move in Standard Algebraic Notation like "Nxe5+"
+	SAN string
+}
+
+// UCI returns the move in the long algebraic notation of the Universal Chess Interface like "e7e8q".
+func (m Move) UCI() string {
+	return m.From.String() + m.To.String() + string(m.Promotion)
+}
+
+func (m Move) String() string {
+	return m.SAN
+}
+
+// long algebraic coordinates like "e2e4", "e2-e4", "e4xd5" or "e7e8q"
+var coordinateRegexp = regexp.MustCompile(`^([a-h][1-8])[-x]?([a-h][1-8])=?([qrbnkQRBNK]?)$`)
+
+// pawn moves to the last rank with the promotion but without "=" like "e8Q" or "exd1n"
+var missingPromotionRegexp = regexp.MustCompile(`^([a-h](?:x[a-h])?[18])([qrbnkQRBNK])$`)
+
+// resolveMove finds the legal move that matches the given move in SAN.
+func (b *Board) resolveMove(move string) (Move, error) {
+	var (
+		piece     string
+		to        string
+		promotion string
+		// if the move is ambiguous, the originating square rank must be given
+		// see https://en.wikipedia.org/wiki/Algebraic_notation_(chess)#Disambiguating_moves
+		fromX         int
+		fromY         int
+		toX           int
+		toY           int
+		name          PieceName
+		promotionName PieceName
+		// moves that match but might leave the king in check
+		candidates []Move
+		legal      []Move
+		err        error
+	)
+
+	san := strings.TrimRight(move, "+#")
+
+	if match := coordinateRegexp.FindStringSubmatch(san); match != nil {
+		return b.resolveCoordinates(move, match[1], match[2], PieceName(strings.ToLower(match[3])))
+	}
+
+	san = missingPromotionRegexp.ReplaceAllString(san, "$1=$2")
+
+	if parts := strings.Split(san, "="); len(parts) > 1 {
+		promotion = parts[1]
+		san = parts[0]
+	}
+
+	if san == "O-O" || san == "O-O-O" {
+		return b.resolveCastle(move, san)
+	}
+
+	if piece, fromX, fromY, to, err = parseMove(san); err != nil {
+		// the move without check marks and promotion is not what the player wrote
+		return Move{}, parseError("move", "%s", move)
+	}
+
+	if toX, toY, err = getXY(to); err != nil {
+		return Move{}, parseError("move", "%s: %v", move, err)
+	}
+
+	if p := b.pieceOn(toY*8 + toX); p != nil && p.Color == b.turn {
+		return Move{}, illegalMove(move, "position %s blocked by %s", to, p)
+	}
+
+	name = PieceName(strings.ToLower(piece))
+	if _, ok := pieceNames[name]; !ok {
+		return Move{}, parseError("move", "%s", move)
+	}
+
+	lastY := 0
+	if b.turn == Dark {
+		lastY = 7
+	}
+
+	if promotion != "" {
+		promotionName = PieceName(strings.ToLower(promotion))
+		if !slices.Contains(b.Variant().Promotions(), promotionName) {
+			return Move{}, illegalMove(move, "invalid promotion: %s", promotion)
+		}
+	} else if name == Pawn && toY == lastY {
+		return Move{}, illegalMove(move, "missing promotion")
+	}
+
+	for _, m := range b.pseudoLegalMoves() {
+		if m.Piece != name || m.To != (Square{X: toX, Y: toY}) || m.Promotion != promotionName || m.Castle != NoCastle {
+			continue
+		}
+
+		if (fromX != -1 && m.From.X != fromX) || (fromY != -1 && m.From.Y != fromY) {
+			continue
+		}
+
+		// pawns change files only when capturing and captures must name the file of the pawn
+		if name == Pawn && (fromX == -1) != (m.From.X == toX) {
+			continue
+		}
+
+		candidates = append(candidates, m)
+		if b.Variant().CheckMove(b, m) == nil {
+			legal = append(legal, m)
+		}
+	}
+
+	switch {
+	case len(candidates) == 0 && name == Pawn && fromX != -1:
+		return Move{}, illegalMove(move, "pawn can't capture on %s", to)
+	case len(candidates) == 0:
+		if reason := b.blockedReason(name, Square{X: toX, Y: toY}); reason != "" {
+			return Move{}, illegalMove(move, "no %s found that can move to %s: %s", pieceNames[name], to, reason)
+		}
+		return Move{}, illegalMove(move, "no %s found that can move to %s", pieceNames[name], to)
+	case len(legal) == 0:
+		return Move{}, illegalMove(move, "%v", b.Variant().CheckMove(b, candidates[0]))
+	case len(legal) > 1:
+		for i := range legal {
+			legal[i].SAN = b.SAN(legal[i])
+		}
+		slices.SortFunc(legal, func(a, b Move) int {
+			return strings.Compare(a.From.String(), b.From.String())
+		})
+		return Move{}, &AmbiguousMoveError{Move: move, Candidates: legal}
+	}
+
+	return legal[0], nil
+}
+
+// resolveCoordinates finds the legal move between the given squares.
+func (b *Board) resolveCoordinates(move string, from string, to string, promotion PieceName) (Move, error) {
+	fromX, fromY, _ := getXY(from)
+	p := b.pieceOn(fromY*8 + fromX)
+	if p == nil || p.Color != b.turn {
+		return Move{}, illegalMove(move, "no piece to move on %s", from)
+	}
+
+	if promotion != "" && !slices.Contains(b.Variant().Promotions(), promotion) {
+		return Move{}, illegalMove(move, "invalid promotion: %s", promotion)
+	}
+
+	for _, m := range b.pseudoLegalMoves() {
+		if m.From.String() != from || m.To.String() != to {
+			continue
+		}
+
+		if m.Promotion != promotion && promotion != "" {
+			continue
+		}
+
+		if err := b.Variant().CheckMove(b, m); err != nil {
+			return Move{}, illegalMove(move, "%v", err)
+		}
+
+		if m.Promotion == promotion {
+			return m, nil
+		}
+
+		return Move{}, illegalMove(move, "missing promotion")
+	}
+
+	return Move{}, illegalMove(move, "%s can't move from %s to %s", p, from, to)
+}
+
+func (b *Board) resolveCastle(move string, san string) (Move, error) {
+	side := KingSide
+	if san == "O-O-O" {
+		side = QueenSide
+	}
+
+	if err := b.checkCastle(side); err != nil {
+		return Move{}, illegalMove(move, "%v", err)
+	}
+
+	for _, m := range b.pseudoLegalMoves() {
+		if m.Castle != side {
+			continue
+		}
+		if err := b.Variant().CheckMove(b, m); err != nil {
+			// king would land on an attacked square
+			return Move{}, illegalMove(move, "%v", err)
+		}
+		return m, nil
+	}
+
+	return Move{}, illegalMove(move, "can't castle")
+}
+
+// makeMove executes a legal move and records it.
+func (b *Board) makeMove(m Move) Move {
+	var (
+		san   = b.san(m)
+		files = b.castleFiles[b.us()]
+		y     = m.From.Y
+	)
+
+	b.saveState()
+	b.play(m)
+
+	b.moveIndicators = []Tile{{m.From.X, y}, {m.To.X, m.To.Y}}
+	switch {
+	case m.EnPassant:
+		b.moveIndicators = append(b.moveIndicators, Tile{m.To.X, y})
+	case m.Castle != NoCastle:
+		kingTo, rookTo := castleTargets(m.Castle)
+		b.moveIndicators = []Tile{{m.From.X, y}, {kingTo, y}, {rookTo, y}, {files.rook(m.Castle), y}}
+	}
+
+	// check marks mean nothing if kings can be captured
+	m.Check = b.Variant().RoyalKing() && b.InCheck()
+	m.Checkmate = m.Check && len(b.LegalMoves()) == 0
+	m.SAN = san + checkSuffix(m.Check, m.Checkmate)
+
+	b.Moves = append(b.Moves, m)
+
+	b.recordPosition()
+
+	return m
+}
+
+// play moves the pieces and updates castling rights, en passant square, clocks and turn.
+func (b *Board) play(m Move) {
+	// remove old castling rights and en passant square from hash
+	b.hash ^= zobrist.castling[b.castling]
+	if b.enPassantHashed() {
+		b.hash ^= zobrist.enPassant[b.enPassant.X]
+	}
+
+	b.applyMove(m)
+
+	// opponent can capture en passant on the skipped square in the next move
+	b.enPassant = nil
+	if m.Piece == Pawn && (m.To.Y-m.From.Y == 2 || m.From.Y-m.To.Y == 2) {
+		b.enPassant = &Square{X: m.From.X, Y: (m.From.Y + m.To.Y) / 2}
+	}
+
+	b.updateCastlingRights()
+
+	// captures and pawn moves reset the halfmove clock
+	if m.Piece == Pawn || m.Captured != "" {
+		b.halfmoveClock = 0
+	} else {
+		b.halfmoveClock++
+	}
+
+	if b.turn == Light {
+		b.turn = Dark
+	} else {
+		b.turn = Light
+		b.fullmoveNumber++
+	}
+
+	b.hash ^= zobrist.turn ^ zobrist.castling[b.castling]
+	if b.enPassantHashed() {
+		b.hash ^= zobrist.enPassant[b.enPassant.X]
+	}
+}
+
+// SAN returns the given legal move in Standard Algebraic Notation
+// with minimal disambiguation and a suffix for check or checkmate.
+func (b *Board) SAN(m Move) string {
+	tmp := *b
+	tmp.play(m)
+
+	check := tmp.Variant().RoyalKing() && tmp.InCheck()
+	return b.san(m) + checkSuffix(check, check && len(tmp.LegalMoves()) == 0)
+}
+
+// san returns the move in SAN without the check or checkmate suffix.
+func (b *Board) san(m Move) string {
+	switch m.Castle {
+	case KingSide:
+		return "O-O"
+	case QueenSide:
+		return "O-O-O"
+	}
+
+	var sb strings.Builder
+
+	if m.Piece == Pawn {
+		if m.Captured != "" {
+			// pawn captures always name the file of the pawn
+			sb.WriteString(m.From.String()[:1] + "x")
+		}
+		sb.WriteString(m.To.String())
+		if m.Promotion != "" {
+			sb.WriteString("=" + strings.ToUpper(string(m.Promotion)))
+		}
+		return sb.String()
+	}
+
+	sb.WriteString(strings.ToUpper(string(m.Piece)))
+	sb.WriteString(b.disambiguation(m))
+	if m.Captured != "" {
+		sb.WriteString("x")
+	}
+	sb.WriteString(m.To.String())
+
+	return sb.String()
+}
+
+// disambiguation returns the file, rank or square of the moving piece
+// if another piece of the same type can legally move to the same square.
+// The file is preferred over the rank and the full square is only used if neither is unique.
+func (b *Board) disambiguation(m Move) string {
+	var ambiguous, sameFile, sameRank bool
+
+	for _, o := range b.LegalMoves() {
+		if o.Piece != m.Piece || o.To != m.To || o.From == m.From {
+			continue
+		}
+		ambiguous = true
+		sameFile = sameFile || o.From.X == m.From.X
+		sameRank = sameRank || o.From.Y == m.From.Y
+	}
+
+	from := m.From.String()
+	switch {
+	case !ambiguous:
+		return ""
+	case !sameFile:
+		return from[:1]
+	case !sameRank:
+		return from[1:]
+	default:
+		return from
+	}
+}
+
+func checkSuffix(check bool, checkmate bool) string {
+	if checkmate {
+		return "#"
+	}
+	if check {
+		return "+"
+	}
+	return ""
+}
+
+// After returns the position after the given legal move without recording the move.
+// The returned board has no move history. It's meant for searches which try many moves.
+func (b *Board) After(m Move) *Board {
+	next := *b
+	next.positions = nil
+	next.history = nil
+	next.Moves = nil
+	next.moveIndicators = nil
+	next.play(m)
+	return &next
+}
@@ -0,0 +1,237 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardMoveReturnsMove(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Qh5 Nc6")
+
+	m, err := b.Move("Qxf7")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "h5", m.From.String())
+	assert.Equal(t, "f7", m.To.String())
+	assert.Equal(t, chess.Queen, m.Piece)
+	assert.Equal(t, chess.Pawn, m.Captured)
+	assert.True(t, m.Check)
+	assert.False(t, m.Checkmate)
+	assert.Equal(t, "Qxf7+", m.SAN)
+	assert.Equal(t, "h5f7", m.UCI())
+
+	assert.Len(t, b.Moves, 5)
+	assert.Equal(t, m, b.Moves[4])
+	assert.Equal(t, "e2e4", b.Moves[0].UCI())
+}
+
+func TestBoardMoveReturnsCastle(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nc6 Bc4 Bc5")
+
+	m, err := b.Move("O-O")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, chess.KingSide, m.Castle)
+	assert.Equal(t, chess.King, m.Piece)
+	assert.Equal(t, "e1g1", m.UCI())
+	assert.Equal(t, "O-O", m.SAN)
+}
+
+func TestBoardMoveReturnsPromotion(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "a4 e6 a5 e5 a6 e4 axb7 e3")
+
+	assertMoveError(t, b, "bxa8", "missing promotion")
+
+	m, err := b.Move("bxa8=q")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, chess.Queen, m.Promotion)
+	assert.Equal(t, chess.Rook, m.Captured)
+	assert.Equal(t, "b7a8q", m.UCI())
+	assert.Equal(t, "bxa8=Q", m.SAN)
+	assertPiece(t, b, "a8", chess.Queen, chess.Light)
+	assert.NotNil(t, b.At("a8").Image)
+
+	// the "=" of promotions can be omitted
+	for move, expected := range map[string]string{"bxa8Q": "bxa8=Q", "bxa8q": "bxa8=Q", "bxc8N+": "bxc8=N"} {
+		b := chess.NewBoard()
+		assertParse(t, b, "a4 e6 a5 e5 a6 e4 axb7 e3")
+
+		m, err := b.Move(move)
+		if assert.NoError(t, err, move) {
+			assert.Equal(t, expected, m.SAN, move)
+		}
+	}
+
+	b, err = chess.NewBoardFromFEN("7k/4P3/8/8/8/8/8/K7 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	m, err = b.Move("e8q")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "e8=Q+", m.SAN)
+	}
+}
+
+func TestBoardSAN(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	// redundant disambiguation and lowercase pieces are normalized
+	assertParse(t, b, "e4 e5 Ng1f3 nc6 Bf1c4 Ng8f6 Nb1c3 Bf8c5 d3 d6")
+	assert.Equal(t, "`1.e4 e5 2.Nf3 Nc6 3.Bc4 Nf6 4.Nc3 Bc5 5.d3 d6 `", b.AlgebraicNotation())
+
+	// knights on b1 and f3 can both move to d2
+	b = chess.NewBoard()
+	assertParse(t, b, "Nf3 e5 d3 e4 N1d2")
+	assert.Equal(t, "Nbd2", b.Moves[len(b.Moves)-1].SAN)
+
+	// rooks on the same file need the rank
+	b, err := chess.NewBoardFromFEN("4k3/8/8/R7/8/8/8/R3K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertParse(t, b, "Ra5a3")
+	assert.Equal(t, "R5a3", b.Moves[0].SAN)
+
+	// queens sharing file and rank with the moving queen need the full square
+	b, err = chess.NewBoardFromFEN("8/7k/8/Q7/8/8/7K/Q3Q3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	for _, m := range b.LegalMoves() {
+		if m.From.String() == "a1" && m.To.String() == "e5" {
+			assert.Equal(t, "Qa1e5", b.SAN(m))
+		}
+	}
+	assertParse(t, b, "Qa1e5")
+	assert.Equal(t, "Qa1e5", b.Moves[0].SAN)
+
+	// pinned pieces don't need to be disambiguated
+	b, err = chess.NewBoardFromFEN("k3r3/8/8/8/8/8/2N1N3/4K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assertParse(t, b, "Ncd4")
+	assert.Equal(t, "Nd4", b.Moves[0].SAN)
+}
+
+func TestBoardMoveCoordinates(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e2e4 e7-e5 g1f3 b8c6 f1b5 g8f6 e1g1 f6xe4")
+	assert.Equal(t, "`1.e4 e5 2.Nf3 Nc6 3.Bb5 Nf6 4.O-O Nxe4 `", b.AlgebraicNotation())
+	assertPiece(t, b, "g1", chess.King, chess.Light)
+	assertPiece(t, b, "f1", chess.Rook, chess.Light)
+
+	assertMoveError(t, b, "e3e4", "no piece to move on e3")
+	assertMoveError(t, b, "e8e7", "no piece to move on e8")
+	assertMoveError(t, b, "d2d5", "white pawn can't move from d2 to d5")
+
+	b = chess.NewBoard()
+
+	assertParse(t, b, "a4 e6 a5 e5 a6 e4 axb7 e3")
+
+	assertMoveError(t, b, "b7a8", "missing promotion")
+
+	m, err := b.Move("b7a8n")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "bxa8=N", m.SAN)
+	assertPiece(t, b, "a8", chess.Knight, chess.Light)
+}
+
+func TestBoardMoveDisambiguation(t *testing.T) {
+	t.Parallel()
+
+	// two bishops can capture on e5
+	b, err := chess.NewBoardFromFEN("4k3/8/5B2/4n3/3B4/8/8/4K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertMoveError(t, b, "Bxe5", "move ambiguous: 2 bishops can move to e5 from d4 and f6")
+	assertMoveError(t, b, "Bgxe5", "no bishop found that can move to e5")
+	assertParse(t, b, "Bfxe5")
+	assertPiece(t, b, "e5", chess.Bishop, chess.Light)
+	assertPiece(t, b, "d4", chess.Bishop, chess.Light)
+	assert.Equal(t, "Bfxe5", b.Moves[0].SAN)
+
+	// two queens on the same file can move to e4
+	b, err = chess.NewBoardFromFEN("Q7/8/7k/8/Q7/8/8/4K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertMoveError(t, b, "Qe4", "move ambiguous: 2 queens can move to e4 from a4 and a8")
+	assertMoveError(t, b, "Qae4", "move ambiguous: 2 queens can move to e4 from a4 and a8")
+	assertParse(t, b, "Q4e4")
+	assertPiece(t, b, "e4", chess.Queen, chess.Light)
+	assertPiece(t, b, "a8", chess.Queen, chess.Light)
+	assert.Equal(t, "Q4e4", b.Moves[0].SAN)
+
+	// three queens can capture on e1
+	b, err = chess.NewBoardFromFEN("2k5/8/8/8/4Q2Q/8/K7/4r2Q w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertMoveError(t, b, "Qxe1", "move ambiguous: 3 queens can move to e1 from e4, h1 and h4")
+	assertMoveError(t, b, "Qhxe1", "move ambiguous: 2 queens can move to e1 from h1 and h4")
+	assertMoveError(t, b, "Qh9xe1", "invalid move: Qh9xe1")
+	assertMoveError(t, b, "Qh9", "invalid move: Qh9: square does not exist: h9")
+	assertMoveError(t, b, "Qzz1+", "invalid move: Qzz1+")
+	assertParse(t, b, "Qh4xe1")
+	assertPiece(t, b, "e1", chess.Queen, chess.Light)
+	assertPiece(t, b, "h1", chess.Queen, chess.Light)
+	assertPiece(t, b, "e4", chess.Queen, chess.Light)
+	assertNoPiece(t, b, "h4")
+	assert.Equal(t, "Qh4xe1", b.Moves[0].SAN)
+}
+
+func TestBoardAfter(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5")
+
+	var nf3 chess.Move
+	for _, m := range b.LegalMoves() {
+		if m.UCI() == "g1f3" {
+			nf3 = m
+		}
+	}
+
+	next := b.After(nf3)
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/4p3/4P3/5N2/PPPP1PPP/RNBQKB1R b KQkq - 1 2", next.FEN())
+	assert.Empty(t, next.Moves)
+
+	// original board is unchanged
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2", b.FEN())
+	assert.Len(t, b.Moves, 2)
+}
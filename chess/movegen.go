@@ -0,0 +1,183 @@
+package chess
+
+var (
+	knightOffsets = []Square{{1, -2}, {2, -1}, {2, 1}, {1, 2}, {-1, 2}, {-2, 1}, {-2, -1}, {-1, -2}}
+	kingOffsets   = []Square{{0, -1}, {1, -1}, {1, 0}, {1, 1}, {0, 1}, {-1, 1}, {-1, 0}, {-1, -1}}
+	promotions    = []PieceName{Queen, Rook, Bishop, Knight}
+)
+
+// LegalMoves returns all moves the player whose turn it is can make
+// without leaving their own king in check or breaking other rules of the variant.
+func (b *Board) LegalMoves() []Move {
+	var (
+		moves   []Move
+		variant = b.Variant()
+	)
+
+	for _, m := range b.pseudoLegalMoves() {
+		if variant.CheckMove(b, m) == nil {
+			moves = append(moves, m)
+		}
+	}
+
+	return moves
+}
+
+// pseudoLegalMoves returns all moves that follow the movement rules of the pieces
+// but might leave the own king in check.
+func (b *Board) pseudoLegalMoves() []Move {
+	var (
+		moves    = make([]Move, 0, 64)
+		us       = b.us()
+		own      = b.occupied[us]
+		occupied = b.occupancy()
+		pieces   = &b.pieces[us]
+	)
+
+	for bb := pieces[pawnIndex]; bb != 0; bb &= bb - 1 {
+		moves = b.appendPawnMoves(moves, bb.lowest())
+	}
+
+	for bb := pieces[knightIndex]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+		moves = b.appendTargets(moves, from, Knight, knightAttacks[from]&^own)
+	}
+
+	for bb := pieces[bishopIndex]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+		moves = b.appendTargets(moves, from, Bishop, bishopAttacks(from, occupied)&^own)
+	}
+
+	for bb := pieces[rookIndex]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+		moves = b.appendTargets(moves, from, Rook, rookAttacks(from, occupied)&^own)
+	}
+
+	for bb := pieces[queenIndex]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+		moves = b.appendTargets(moves, from, Queen, (bishopAttacks(from, occupied)|rookAttacks(from, occupied))&^own)
+	}
+
+	for bb := pieces[kingIndex]; bb != 0; bb &= bb - 1 {
+		from := bb.lowest()
+		moves = b.appendTargets(moves, from, King, kingAttacks[from]&^own)
+		moves = b.appendCastleMoves(moves, squareOf(from))
+	}
+
+	return moves
+}
+
+func (b *Board) appendPawnMoves(moves []Move, from int) []Move {
+	var (
+		us       = b.us()
+		occupied = b.occupancy()
+		// light pawns move up the board (towards y = 0)
+		dir, startY, lastY = -8, 6, 0
+	)
+
+	if us == black {
+		dir, startY, lastY = 8, 1, 7
+	}
+
+	appendMove := func(to int, captured PieceName) {
+		m := Move{From: squareOf(from), To: squareOf(to), Piece: Pawn, Captured: captured}
+		if to/8 == lastY {
+			for _, promotion := range b.Variant().Promotions() {
+				m.Promotion = promotion
+				moves = append(moves, m)
+			}
+			return
+		}
+		moves = append(moves, m)
+	}
+
+	if to := from + dir; to >= 0 && to < 64 && occupied&(1<<to) == 0 {
+		appendMove(to, "")
+
+		to += dir
+		if from/8 == startY && occupied&(1<<to) == 0 {
+			appendMove(to, "")
+		}
+	}
+
+	for targets := pawnAttacks[us][from] & b.occupied[us.other()]; targets != 0; targets &= targets - 1 {
+		to := targets.lowest()
+		appendMove(to, b.nameAt(to))
+	}
+
+	if b.enPassant != nil && pawnAttacks[us][from]&(1<<b.enPassant.index()) != 0 {
+		moves = append(moves, Move{From: squareOf(from), To: *b.enPassant, Piece: Pawn, Captured: Pawn, EnPassant: true})
+	}
+
+	return moves
+}
+
+func (b *Board) appendTargets(moves []Move, from int, name PieceName, targets bitboard) []Move {
+	for ; targets != 0; targets &= targets - 1 {
+		to := targets.lowest()
+		moves = append(moves, Move{From: squareOf(from), To: squareOf(to), Piece: name, Captured: b.nameAt(to)})
+	}
+
+	return moves
+}
+
+func (b *Board) appendCastleMoves(moves []Move, from Square) []Move {
+	for _, side := range []CastleSide{KingSide, QueenSide} {
+		if b.checkCastle(side) == nil {
+			moves = append(moves, Move{From: from, To: b.castleTo(side), Piece: King, Castle: side})
+		}
+	}
+
+	return moves
+}
+
+// isLegal returns true if the move does not leave the own king in check.
+func (b *Board) isLegal(m Move) bool {
+	tmp := *b
+	tmp.applyMove(m)
+	return !tmp.InCheck()
+}
+
+// applyMove moves the pieces on the board according to the given move.
+// It does not validate the move, switch turns or record the move.
+func (b *Board) applyMove(m Move) {
+	from, to := m.From.index(), m.To.index()
+
+	piece, s := b.pieceAt(from)
+
+	if m.Castle != NoCastle {
+		// in Chess960, the king or the rook might already be on its destination
+		y := m.From.Y
+		kingTo, rookTo := castleTargets(m.Castle)
+		b.removePiece(from)
+		b.removePiece(y*8 + b.castleFiles[s].rook(m.Castle))
+		b.putPiece(y*8+kingTo, kingIndex, s)
+		b.putPiece(y*8+rookTo, rookIndex, s)
+		return
+	}
+
+	if m.Promotion != "" {
+		piece = pieceIndex(m.Promotion)
+	}
+
+	b.removePiece(from)
+	b.removePiece(to)
+	b.putPiece(to, piece, s)
+
+	if m.EnPassant {
+		// captured pawn is next to the capturing pawn
+		b.removePiece(Square{X: m.To.X, Y: m.From.Y}.index())
+	}
+}
+
+// nameAt returns the name of the piece on the given square or an empty name if there is none.
+func (b *Board) nameAt(sq int) PieceName {
+	if piece, _ := b.pieceAt(sq); piece != -1 {
+		return pieceOrder[piece]
+	}
+	return ""
+}
+
+func inBounds(x int, y int) bool {
+	return x >= 0 && x < 8 && y >= 0 && y < 8
+}
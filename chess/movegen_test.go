@@ -0,0 +1,138 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLegalMovesInitial(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	moves := b.LegalMoves()
+	assert.Len(t, moves, 20)
+	assertLegalMove(t, moves, "e2", "e4")
+	assertLegalMove(t, moves, "g1", "f3")
+	assertNoLegalMove(t, moves, "e2", "e5")
+	assertNoLegalMove(t, moves, "f1", "c4")
+
+	assertParse(t, b, "e4")
+
+	moves = b.LegalMoves()
+	assert.Len(t, moves, 20)
+	assertLegalMove(t, moves, "e7", "e5")
+	assertLegalMove(t, moves, "b8", "c6")
+}
+
+func TestLegalMovesCheck(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Qh5 Nc6 Qxf7")
+
+	// only the king can capture the queen
+	moves := b.LegalMoves()
+	assert.Len(t, moves, 1)
+	assertLegalMove(t, moves, "e8", "f7")
+	assert.Equal(t, chess.Queen, moves[0].Captured)
+
+	b = chess.NewBoard()
+
+	// scholar's mate
+	assertParse(t, b, "e4 e5 Qh5 Nc6 Bc4 Nf6 Qxf7")
+
+	assert.Empty(t, b.LegalMoves())
+}
+
+func TestLegalMovesPin(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "d4 e5 Nc3 Bb4")
+
+	moves := b.LegalMoves()
+	assertNoLegalMove(t, moves, "c3", "e4")
+	assertNoLegalMove(t, moves, "c3", "b5")
+	assertLegalMove(t, moves, "a2", "a3")
+}
+
+func TestLegalMovesCastle(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nf6 Bc4 Bc5")
+
+	moves := b.LegalMoves()
+	m := assertLegalMove(t, moves, "e1", "g1")
+	assert.Equal(t, chess.KingSide, m.Castle)
+
+	assertParse(t, b, "d3 d6 Nc3 Bg4 Be3 Nc6 Qd2 Qd7")
+
+	moves = b.LegalMoves()
+	m = assertLegalMove(t, moves, "e1", "c1")
+	assert.Equal(t, chess.QueenSide, m.Castle)
+
+	// castling through an attacked square is not allowed
+	assertParse(t, b, "h3 Bxf3")
+
+	moves = b.LegalMoves()
+	assertNoLegalMove(t, moves, "e1", "c1")
+	assertLegalMove(t, moves, "e1", "g1")
+}
+
+func TestLegalMovesEnPassant(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 a6 e5 d5")
+
+	m := assertLegalMove(t, b.LegalMoves(), "e5", "d6")
+	assert.True(t, m.EnPassant)
+	assert.Equal(t, chess.Pawn, m.Captured)
+
+	assertParse(t, b, "a3 h6")
+
+	assertNoLegalMove(t, b.LegalMoves(), "e5", "d6")
+}
+
+func TestLegalMovesPromotion(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "a4 e6 a5 e5 a6 e4 axb7 e3")
+
+	var promotions []chess.PieceName
+	for _, m := range b.LegalMoves() {
+		if m.From.String() == "b7" && m.To.String() == "a8" {
+			assert.Equal(t, chess.Rook, m.Captured)
+			promotions = append(promotions, m.Promotion)
+		}
+	}
+	assert.ElementsMatch(t, []chess.PieceName{chess.Queen, chess.Rook, chess.Bishop, chess.Knight}, promotions)
+}
+
+func assertLegalMove(t *testing.T, moves []chess.Move, from string, to string) chess.Move {
+	for _, m := range moves {
+		if m.From.String() == from && m.To.String() == to {
+			return m
+		}
+	}
+	assert.Fail(t, "move not found", "expected legal move from %s to %s", from, to)
+	return chess.Move{}
+}
+
+func assertNoLegalMove(t *testing.T, moves []chess.Move, from string, to string) {
+	for _, m := range moves {
+		if m.From.String() == from && m.To.String() == to {
+			assert.Fail(t, "move found", "expected no legal move from %s to %s", from, to)
+		}
+	}
+}
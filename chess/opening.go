@@ -0,0 +1,207 @@
+package chess
+
+import (
+	_ "embed"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// eco.tsv lists openings by their code in the Encyclopaedia of Chess Openings
+// with the moves that lead to them.
+//
+//go:embed eco.tsv
+var ecoTSV string
+
+// book.tsv lists sound lines the engine may play with weights for how often it should play them.
+// It is separate from eco.tsv since not every named opening is worth playing.
+//
+//go:embed book.tsv
+var bookTSV string
+
+type Opening struct {
+	ECO  string
+	Name string
+}
+
+func (o Opening) String() string {
+	return fmt.Sprintf("%s %s", o.ECO, o.Name)
+}
+
+// BookMove is a move of the opening book with the weight of the lines it's part of.
+type BookMove struct {
+	Move
+	Weight int
+}
+
+type openingBook struct {
+	// openings by the hash of the position they lead to
+	openings map[uint64]Opening
+	// hashes of all positions on the way to the openings
+	positions map[uint64]bool
+}
+
+type moveBook struct {
+	// weights of moves in UCI notation by position hash
+	moves map[uint64]map[string]int
+}
+
+// ecoBook and engineBook are loaded on first use since they need to play through all lines.
+var (
+	ecoBook = sync.OnceValue(func() *openingBook {
+		book, err := loadOpeningBook(ecoTSV)
+		if err != nil {
+			panic(err)
+		}
+		return book
+	})
+	engineBook = sync.OnceValue(func() *moveBook {
+		book, err := loadMoveBook(bookTSV)
+		if err != nil {
+			panic(err)
+		}
+		return book
+	})
+)
+
+func loadOpeningBook(tsv string) (*openingBook, error) {
+	book := &openingBook{openings: make(map[uint64]Opening), positions: make(map[uint64]bool)}
+
+	rows, err := readTSV(tsv, 3)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fields := range rows {
+		b, err := playLine(i, fields[2], func(hash uint64, m Move) {
+			book.positions[hash] = true
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		// the position is in the book even if no moves follow
+		book.positions[b.Hash()] = true
+		book.openings[b.Hash()] = Opening{ECO: fields[0], Name: fields[1]}
+	}
+
+	return book, nil
+}
+
+func loadMoveBook(tsv string) (*moveBook, error) {
+	book := &moveBook{moves: make(map[uint64]map[string]int)}
+
+	rows, err := readTSV(tsv, 2)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, fields := range rows {
+		weight, err := strconv.Atoi(fields[0])
+		if err != nil || weight <= 0 {
+			return nil, fmt.Errorf("invalid opening in line %d: invalid weight: %s", i+2, fields[0])
+		}
+
+		_, err = playLine(i, fields[1], func(hash uint64, m Move) {
+			if book.moves[hash] == nil {
+				book.moves[hash] = make(map[string]int)
+			}
+			book.moves[hash][m.UCI()] += weight
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return book, nil
+}
+
+// readTSV returns the fields of all lines after the header.
+func readTSV(tsv string, n int) ([][]string, error) {
+	var rows [][]string
+
+	lines := strings.Split(strings.TrimSpace(tsv), "\n")
+	for i, line := range lines[1:] {
+		fields := strings.Split(line, "\t")
+		if len(fields) != n {
+			return nil, fmt.Errorf("invalid opening in line %d: expected %d fields but got %d", i+2, n, len(fields))
+		}
+		rows = append(rows, fields)
+	}
+
+	return rows, nil
+}
+
+// playLine plays the moves of the row with the given index from the start position.
+// onMove is called for each move with the hash of the position before the move.
+func playLine(i int, movetext string, onMove func(hash uint64, m Move)) (*Board, error) {
+	b := NewBoard()
+
+	for _, move := range strings.Fields(movetext) {
+		if move = moveNumberRegexp.ReplaceAllString(move, ""); move == "" {
+			continue
+		}
+
+		hash := b.Hash()
+
+		m, err := b.Move(move)
+		if err != nil {
+			return nil, fmt.Errorf("invalid opening in line %d: %v", i+2, err)
+		}
+
+		onMove(hash, m)
+	}
+
+	return b, nil
+}
+
+// Opening returns the last opening the game reached.
+// It returns false if the current position is no longer in the opening book.
+func (b *Board) Opening() (Opening, bool) {
+	book := ecoBook()
+
+	if !book.positions[b.hash] {
+		return Opening{}, false
+	}
+
+	if o, ok := book.openings[b.hash]; ok {
+		return o, true
+	}
+
+	for i := len(b.history) - 1; i >= 0; i-- {
+		if o, ok := book.openings[b.history[i].hash]; ok {
+			return o, true
+		}
+	}
+
+	return Opening{}, false
+}
+
+// BookMoves returns the moves the engine may play to continue the game in the opening book
+// ordered by their weight.
+func (b *Board) BookMoves() []BookMove {
+	var (
+		moves []BookMove
+		legal = b.LegalMoves()
+	)
+
+	for uci, weight := range engineBook().moves[b.hash] {
+		for _, m := range legal {
+			if m.UCI() == uci {
+				m.SAN = b.SAN(m)
+				moves = append(moves, BookMove{Move: m, Weight: weight})
+			}
+		}
+	}
+
+	slices.SortFunc(moves, func(a, b BookMove) int {
+		if a.Weight != b.Weight {
+			return b.Weight - a.Weight
+		}
+		return strings.Compare(a.SAN, b.SAN)
+	})
+
+	return moves
+}
@@ -0,0 +1,75 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardOpening(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	_, ok := b.Opening()
+	assert.False(t, ok)
+
+	assertParse(t, b, "e4 e5 Nf3 Nc6")
+	assertOpening(t, b, "C44 King's Knight Opening: Normal Variation")
+
+	assertParse(t, b, "Bb5")
+	assertOpening(t, b, "C60 Ruy Lopez")
+
+	// positions between named openings are still in the book
+	assertParse(t, b, "a6 Ba4")
+	assertOpening(t, b, "C70 Ruy Lopez: Morphy Defense")
+
+	assertParse(t, b, "Nf6 O-O Be7")
+	assertOpening(t, b, "C84 Ruy Lopez: Closed")
+
+	assertParse(t, b, "Re1")
+	_, ok = b.Opening()
+	assert.False(t, ok)
+
+	// openings are found by position so transpositions are recognized
+	b = chess.NewBoard()
+	assertParse(t, b, "c4 Nf6 d4 e6 Nc3 Bb4")
+	assertOpening(t, b, "E20 Nimzo-Indian Defense")
+}
+
+func TestBoardBookMoves(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+	assertParse(t, b, "e4 e5 Nf3 Nc6 Bb5 a6")
+
+	// main lines come first
+	assert.Equal(t, []string{"Ba4", "Bxc6"}, bookMoves(t, b))
+
+	assertParse(t, b, "Bc4")
+	assert.Empty(t, b.BookMoves())
+
+	// named openings are not necessarily worth playing
+	b = chess.NewBoard()
+	assertParse(t, b, "e4 e5")
+	assertOpening(t, b, "C20 King's Pawn Game")
+	assert.NotContains(t, bookMoves(t, b), "Ke2")
+	assert.NotContains(t, bookMoves(t, b), "Qh5")
+}
+
+func bookMoves(t *testing.T, b *chess.Board) []string {
+	var moves []string
+	for _, m := range b.BookMoves() {
+		assert.Positive(t, m.Weight)
+		moves = append(moves, m.SAN)
+	}
+	return moves
+}
+
+func assertOpening(t *testing.T, b *chess.Board, expected string) {
+	o, ok := b.Opening()
+	if assert.True(t, ok, "expected opening %s", expected) {
+		assert.Equal(t, expected, o.String())
+	}
+}
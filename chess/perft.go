@@ -0,0 +1,38 @@
+package chess
+
+// Perft counts the positions reachable with the given number of half moves.
+// The counts of well-known positions are used to verify the move generator.
+// See https://www.chessprogramming.org/Perft_Results
+func (b *Board) Perft(depth int) int {
+	if depth == 0 {
+		return 1
+	}
+
+	moves := b.LegalMoves()
+	if depth == 1 {
+		return len(moves)
+	}
+
+	nodes := 0
+	for _, m := range moves {
+		tmp := *b
+		tmp.play(m)
+		nodes += tmp.Perft(depth - 1)
+	}
+
+	return nodes
+}
+
+// Divide returns the perft count after each legal move in UCI notation.
+// This helps to find the move that is generated incorrectly if the total count is wrong.
+func (b *Board) Divide(depth int) map[string]int {
+	nodes := map[string]int{}
+
+	for _, m := range b.LegalMoves() {
+		tmp := *b
+		tmp.play(m)
+		nodes[m.UCI()] = tmp.Perft(depth - 1)
+	}
+
+	return nodes
+}
@@ -0,0 +1,74 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+// see https://www.chessprogramming.org/Perft_Results
+var perftPositions = []struct {
+	name  string
+	fen   string
+	nodes []int
+}{
+	{"initial", chess.StartFEN, []int{20, 400, 8902, 197281, 4865609}},
+	{"kiwipete", "r3k2r/p1ppqpb1/bn2pnp1/3PN3/1p2P3/2N2Q1p/PPPBBPPP/R3K2R w KQkq - 0 1", []int{48, 2039, 97862, 4085603}},
+	{"position 3", "8/2p5/3p4/KP5r/1R3p1k/8/4P1P1/8 w - - 0 1", []int{14, 191, 2812, 43238, 674624}},
+	{"position 4", "r3k2r/Pppp1ppp/1b3nbN/nP6/BBP1P3/q4N2/Pp1P2PP/R2Q1RK1 w kq - 0 1", []int{6, 264, 9467, 422333}},
+	{"position 5", "rnbq1k1r/pp1Pbppp/2p5/8/2B5/8/PPP1NnPP/RNBQK2R w KQ - 1 8", []int{44, 1486, 62379, 2103487}},
+	{"position 6", "r4rk1/1pp1qppp/p1np1n2/2b1p1B1/2B1P1b1/P1NP1N2/1PP1QPPP/R4RK1 w - - 0 10", []int{46, 2079, 89890, 3894594}},
+	// see https://www.chessprogramming.org/Chess960_Perft_Results
+	{"chess960 1", "bqnb1rkr/pp3ppp/3ppn2/2p5/5P2/P2P4/NPP1P1PP/BQ1BNRKR w HFhf - 2 9", []int{21, 528, 12189, 326672}},
+	{"chess960 2", "2nnrbkr/p1qppppp/8/1ppb4/6PP/3PP3/PPP2P2/BQNNRBKR w HEhe - 1 9", []int{21, 807, 18002, 667366}},
+	{"chess960 3", "b1q1rrkb/pppppppp/3nn3/8/P7/1PPP4/4PPPP/BQNNRKRB w GE - 1 9", []int{20, 479, 10471, 273318}},
+	{"chess960 4", "qbbnnrkr/2pp2pp/p7/1p2pp2/8/P3PP2/1PPP1KPP/QBBNNR1R w hf - 0 9", []int{22, 593, 13440, 382958}},
+	{"chess960 5", "1nbbnrkr/p1p1ppp1/3p4/1p3P1p/3Pq2P/8/PPP1P1P1/QNBBNRKR w HFhf - 0 9", []int{28, 1120, 31058, 1171749}},
+}
+
+func TestPerft(t *testing.T) {
+	t.Parallel()
+
+	for _, p := range perftPositions {
+		t.Run(p.name, func(t *testing.T) {
+			t.Parallel()
+
+			b, err := chess.NewBoardFromFEN(p.fen)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			for i, nodes := range p.nodes {
+				depth := i + 1
+				if testing.Short() && nodes > 100000 {
+					t.Skipf("skipping depth %d in short mode", depth)
+				}
+				assert.Equal(t, nodes, b.Perft(depth), "depth %d", depth)
+			}
+		})
+	}
+}
+
+func TestDivide(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	divide := b.Divide(2)
+	assert.Len(t, divide, 20)
+	assert.Equal(t, 20, divide["e2e4"])
+	assert.Equal(t, 20, divide["g1f3"])
+}
+
+func BenchmarkPerft(b *testing.B) {
+	board, err := chess.NewBoardFromFEN(kiwipete)
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		board.Perft(2)
+	}
+}
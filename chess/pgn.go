@@ -0,0 +1,126 @@
+package chess
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// tags every PGN game must include in this order
+var sevenTagRoster = []string{"Event", "Site", "Date", "Round", "White", "Black", "Result"}
+
+// Annotation is a comment about a move in PGN like "?? Nf3 was best."
+type Annotation struct {
+	// suffix like "!", "?", "!?", "?!" or "??"
+	Symbol  string
+	Comment string
+}
+
+// PGN returns the game in Portable Game Notation.
+// Missing tags of the seven tag roster are set to "?" and the result is always taken from the board.
+func (b *Board) PGN(tags map[string]string) string {
+	return b.AnnotatedPGN(tags, nil)
+}
+
+// AnnotatedPGN is like PGN but adds the annotations to the moves with the same index.
+func (b *Board) AnnotatedPGN(tags map[string]string, annotations []Annotation) string {
+	var sb strings.Builder
+
+	writeTag := func(name string, value string) {
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		value = strings.ReplaceAll(value, `"`, `\"`)
+		fmt.Fprintf(&sb, "[%s \"%s\"]\n", name, value)
+	}
+
+	for _, name := range sevenTagRoster {
+		value := tags[name]
+		switch {
+		case name == "Result":
+			value = string(b.Result())
+		case value == "" && name == "Date":
+			value = "????.??.??"
+		case value == "":
+			value = "?"
+		}
+		writeTag(name, value)
+	}
+
+	switch {
+	case b.Variant() != Standard:
+		writeTag("Variant", b.Variant().Name())
+	case b.chess960:
+		writeTag("Variant", "Chess960")
+	}
+
+	if b.startFEN != "" {
+		writeTag("SetUp", "1")
+		writeTag("FEN", b.startFEN)
+	}
+
+	var names []string
+	for name := range tags {
+		if !slices.Contains(sevenTagRoster, name) && name != "Variant" && name != "SetUp" && name != "FEN" {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		writeTag(name, tags[name])
+	}
+
+	sb.WriteString("\n")
+
+	// movetext lines should not be longer than 80 characters
+	line := ""
+	for _, token := range append(b.movetext(annotations), string(b.Result())) {
+		if line != "" && len(line)+1+len(token) > 80 {
+			sb.WriteString(line + "\n")
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += token
+	}
+	sb.WriteString(line + "\n")
+
+	return sb.String()
+}
+
+// movetext returns the moves with move numbers like "1." or "4..." as separate tokens.
+// Comments are split into words such that long comments can span multiple lines.
+func (b *Board) movetext(annotations []Annotation) []string {
+	var (
+		tokens    []string
+		commented bool
+	)
+
+	for i, m := range b.Moves {
+		ply := b.plyOffset() + i
+		if ply%2 == 0 {
+			tokens = append(tokens, fmt.Sprintf("%d.", ply/2+1))
+		} else if i == 0 || commented {
+			// move number of black is repeated after comments
+			tokens = append(tokens, fmt.Sprintf("%d...", ply/2+1))
+		}
+
+		var a Annotation
+		if i < len(annotations) {
+			a = annotations[i]
+		}
+
+		tokens = append(tokens, m.SAN+a.Symbol)
+
+		commented = strings.TrimSpace(a.Comment) != ""
+		if commented {
+			// comments can't contain closing braces
+			comment := strings.ReplaceAll(a.Comment, "}", ")")
+			words := strings.Fields(comment)
+			words[0] = "{" + words[0]
+			words[len(words)-1] += "}"
+			tokens = append(tokens, words...)
+		}
+	}
+
+	return tokens
+}
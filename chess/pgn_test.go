@@ -0,0 +1,99 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardPGN(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "f3 e6 g4 Qh4")
+
+	pgn := b.PGN(map[string]string{
+		"Event":     "Stacker News chess game",
+		"Site":      "https://stacker.news/items/1",
+		"Date":      "2024.09.21",
+		"White":     "alice",
+		"Black":     "bob",
+		"Result":    "*",
+		"Annotator": `"ek"`,
+	})
+
+	assert.Equal(t, ``+
+		`[Event "Stacker News chess game"]`+"\n"+
+		`[Site "https://stacker.news/items/1"]`+"\n"+
+		`[Date "2024.09.21"]`+"\n"+
+		`[Round "?"]`+"\n"+
+		`[White "alice"]`+"\n"+
+		`[Black "bob"]`+"\n"+
+		`[Result "0-1"]`+"\n"+
+		`[Annotator "\"ek\""]`+"\n"+
+		"\n"+
+		"1. f3 e6 2. g4 Qh4# 0-1\n", pgn)
+}
+
+func TestBoardPGNCustomPosition(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewBoardFromFEN("4k3/1P6/8/8/8/8/8/4K3 b - - 0 40")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assertParse(t, b, "Kd7 b8=Q Kc6")
+
+	assert.Equal(t, ``+
+		`[Event "?"]`+"\n"+
+		`[Site "?"]`+"\n"+
+		`[Date "????.??.??"]`+"\n"+
+		`[Round "?"]`+"\n"+
+		`[White "?"]`+"\n"+
+		`[Black "?"]`+"\n"+
+		`[Result "*"]`+"\n"+
+		`[SetUp "1"]`+"\n"+
+		`[FEN "4k3/1P6/8/8/8/8/8/4K3 b - - 0 40"]`+"\n"+
+		"\n"+
+		"40... Kd7 41. b8=Q Kc6 *\n", b.PGN(nil))
+}
+
+func TestBoardPGNLineLength(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Nf3 Nc6 Bb5 a6 Ba4 Nf6 O-O Be7 Re1 b5 Bb3 d6 c3 O-O h3 Nb8 d4 Nbd7")
+
+	pgn := b.PGN(nil)
+	assert.Contains(t, pgn, "\n\n"+
+		"1. e4 e5 2. Nf3 Nc6 3. Bb5 a6 4. Ba4 Nf6 5. O-O Be7 6. Re1 b5 7. Bb3 d6 8. c3\n"+
+		"O-O 9. h3 Nb8 10. d4 Nbd7 *\n")
+}
+
+func TestBoardAnnotatedPGN(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5 Qh5 Nc6 Bc4 Nf6 Qxf7")
+
+	pgn := b.AnnotatedPGN(nil, []chess.Annotation{
+		2: {Comment: "Early queen."},
+		4: {Symbol: "!?"},
+		5: {Symbol: "??", Comment: "Blunder. g6 was best."},
+		6: {Comment: "Scholar's mate {again}"},
+	})
+	assert.Contains(t, pgn, "\n\n"+
+		"1. e4 e5 2. Qh5 {Early queen.} 2... Nc6 3. Bc4!? Nf6?? {Blunder. g6 was best.}\n"+
+		"4. Qxf7# {Scholar's mate {again)} 1-0\n")
+
+	// annotations can be read back
+	b, err := chess.NewGameFromPGN(pgn)
+	if assert.NoError(t, err) {
+		assert.Len(t, b.Moves, 7)
+	}
+}
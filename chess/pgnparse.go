@@ -0,0 +1,293 @@
+package chess
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// PGNGame is a game parsed from Portable Game Notation.
+type PGNGame struct {
+	Tags   map[string]string
+	Root   *PGNNode
+	Result Result
+}
+
+// PGNNode is a move in the game tree of a PGN game.
+// The first child continues the current line and all other children are variations.
+// The root node has no move and holds comments before the first move.
+type PGNNode struct {
+	Move     string
+	NAGs     []int
+	Comments []string
+	Parent   *PGNNode
+	Children []*PGNNode
+}
+
+// MainLine returns the moves of the main line in SAN.
+func (g *PGNGame) MainLine() []string {
+	var moves []string
+	for n := g.Root; len(n.Children) > 0; n = n.Children[0] {
+		moves = append(moves, n.Children[0].Move)
+	}
+	return moves
+}
+
+type pgnTokenType int
+
+const (
+	pgnSymbol pgnTokenType = iota
+	pgnString
+	pgnComment
+	pgnNAG
+	pgnOpenBracket
+	pgnCloseBracket
+	pgnOpenParen
+	pgnCloseParen
+)
+
+type pgnToken struct {
+	typ   pgnTokenType
+	value string
+}
+
+var (
+	moveNumberRegexp = regexp.MustCompile(`^[0-9]+\.+`)
+	annotationRegexp = regexp.MustCompile(`[!?]+$`)
+	// NAGs of the traditional suffix annotations
+	suffixAnnotations = map[string]int{"!": 1, "?": 2, "!!": 3, "??": 4, "!?": 5, "?!": 6}
+)
+
+// ParsePGN parses the first game in the given PGN.
+// Tags are optional such that plain movetext like "1. e4 e5 2. Nf3" can also be parsed.
+func ParsePGN(pgn string) (*PGNGame, error) {
+	var (
+		game     = &PGNGame{Tags: map[string]string{}, Root: &PGNNode{}, Result: NoResult}
+		cur      = game.Root
+		stack    []*PGNNode
+		movetext bool
+		tokens   []pgnToken
+		err      error
+	)
+
+	if tokens, err = tokenizePGN(pgn); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+
+		switch t.typ {
+		case pgnOpenBracket:
+			if movetext {
+				// start of next game
+				return game, nil
+			}
+
+			if i+3 >= len(tokens) || tokens[i+1].typ != pgnSymbol || tokens[i+2].typ != pgnString || tokens[i+3].typ != pgnCloseBracket {
+				return nil, parseError("PGN", "invalid tag")
+			}
+			game.Tags[tokens[i+1].value] = tokens[i+2].value
+			i += 3
+
+		case pgnComment:
+			cur.Comments = append(cur.Comments, t.value)
+
+		case pgnNAG:
+			if cur == game.Root {
+				return nil, parseError("PGN", "annotation before first move")
+			}
+			nag, _ := strconv.Atoi(t.value)
+			cur.NAGs = append(cur.NAGs, nag)
+
+		case pgnOpenParen:
+			if cur == game.Root {
+				return nil, parseError("PGN", "variation before first move")
+			}
+			// variation is an alternative to the last move
+			stack = append(stack, cur)
+			cur = cur.Parent
+
+		case pgnCloseParen:
+			if len(stack) == 0 {
+				return nil, parseError("PGN", "unexpected )")
+			}
+			cur = stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+
+		case pgnSymbol:
+			movetext = true
+
+			switch Result(t.value) {
+			case WhiteWins, BlackWins, Draw, NoResult:
+				if len(stack) > 0 {
+					return nil, parseError("PGN", "unterminated variation")
+				}
+				game.Result = Result(t.value)
+				return game, nil
+			}
+
+			node := &PGNNode{Move: t.value, Parent: cur}
+			cur.Children = append(cur.Children, node)
+			cur = node
+
+		default:
+			return nil, parseError("PGN", "unexpected token: %s", t.value)
+		}
+	}
+
+	if len(stack) > 0 {
+		return nil, parseError("PGN", "unterminated variation")
+	}
+
+	return game, nil
+}
+
+func tokenizePGN(pgn string) ([]pgnToken, error) {
+	var (
+		tokens []pgnToken
+		runes  = []rune(pgn)
+	)
+
+	// returns the index of the next rune for which f returns true or the end of input
+	until := func(i int, f func(rune) bool) int {
+		for i < len(runes) && !f(runes[i]) {
+			i++
+		}
+		return i
+	}
+
+	isDelimiter := func(r rune) bool {
+		return strings.ContainsRune(" \t\r\n[](){};\"$", r)
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+
+		switch {
+		case r == ' ' || r == '\t' || r == '\r' || r == '\n':
+			continue
+
+		case r == '%' && (i == 0 || runes[i-1] == '\n'):
+			// escape mechanism: ignore whole line
+			i = until(i, func(r rune) bool { return r == '\n' })
+
+		case r == ';':
+			j := until(i, func(r rune) bool { return r == '\n' })
+			tokens = append(tokens, pgnToken{pgnComment, strings.TrimSpace(string(runes[i+1 : j]))})
+			i = j
+
+		case r == '{':
+			j := until(i, func(r rune) bool { return r == '}' })
+			if j == len(runes) {
+				return nil, parseError("PGN", "unterminated comment")
+			}
+			tokens = append(tokens, pgnToken{pgnComment, strings.TrimSpace(string(runes[i+1 : j]))})
+			i = j
+
+		case r == '"':
+			var sb strings.Builder
+			j := i + 1
+			for ; j < len(runes) && runes[j] != '"'; j++ {
+				if runes[j] == '\\' && j+1 < len(runes) {
+					j++
+				}
+				sb.WriteRune(runes[j])
+			}
+			if j == len(runes) {
+				return nil, parseError("PGN", "unterminated string")
+			}
+			tokens = append(tokens, pgnToken{pgnString, sb.String()})
+			i = j
+
+		case r == '$':
+			j := until(i+1, func(r rune) bool { return r < '0' || r > '9' })
+			if j == i+1 {
+				return nil, parseError("PGN", "invalid NAG")
+			}
+			tokens = append(tokens, pgnToken{pgnNAG, string(runes[i+1 : j])})
+			i = j - 1
+
+		case r == '[':
+			tokens = append(tokens, pgnToken{pgnOpenBracket, "["})
+		case r == ']':
+			tokens = append(tokens, pgnToken{pgnCloseBracket, "]"})
+		case r == '(':
+			tokens = append(tokens, pgnToken{pgnOpenParen, "("})
+		case r == ')':
+			tokens = append(tokens, pgnToken{pgnCloseParen, ")"})
+
+		default:
+			j := until(i, isDelimiter)
+			tokens = append(tokens, symbolTokens(string(runes[i:j]))...)
+			i = j - 1
+		}
+	}
+
+	return tokens, nil
+}
+
+// symbolTokens splits symbols like "12.e4!?" into the move and its annotation.
+// Move numbers are dropped since they are implied by the position in the movetext.
+func symbolTokens(symbol string) []pgnToken {
+	var tokens []pgnToken
+
+	symbol = moveNumberRegexp.ReplaceAllString(symbol, "")
+	// symbols like "..." only indicate that black is to move
+	symbol = strings.TrimLeft(symbol, ".")
+
+	annotation := annotationRegexp.FindString(symbol)
+	symbol = strings.TrimSuffix(symbol, annotation)
+
+	if symbol != "" {
+		tokens = append(tokens, pgnToken{pgnSymbol, symbol})
+	}
+
+	if nag, ok := suffixAnnotations[annotation]; ok {
+		tokens = append(tokens, pgnToken{pgnNAG, strconv.Itoa(nag)})
+	}
+
+	return tokens
+}
+
+// NewGameFromPGN creates a board from the main line of the first game in the given PGN.
+// If the game has a FEN tag, the game starts from that position.
+// Games with a Variant tag like "Chess960" or "Three-check" follow the rules of the variant.
+func NewGameFromPGN(pgn string) (*Board, error) {
+	var (
+		game  *PGNGame
+		board *Board
+		err   error
+	)
+
+	if game, err = ParsePGN(pgn); err != nil {
+		return nil, err
+	}
+
+	chess960 := strings.EqualFold(game.Tags["Variant"], "Chess960")
+	fen, ok := game.Tags["FEN"]
+
+	switch {
+	case ok || chess960:
+		if !ok {
+			fen = StartFEN
+		}
+		if board, err = newBoardFromFEN(fen, chess960); err != nil {
+			return nil, err
+		}
+	default:
+		board = NewBoard()
+	}
+
+	if v, ok := ParseVariant(game.Tags["Variant"]); ok {
+		board.SetVariant(v)
+	}
+
+	for _, move := range game.MainLine() {
+		if _, err = board.Move(move); err != nil {
+			return nil, err
+		}
+	}
+
+	return board, nil
+}
@@ -0,0 +1,132 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParsePGN(t *testing.T) {
+	t.Parallel()
+
+	game, err := chess.ParsePGN(`[Event "F/S Return Match"]
+[Site "Belgrade, Serbia JUG"]
+[Date "1992.11.04"]
+[Round "29"]
+[White "Fischer, Robert J."]
+[Black "Spassky, Boris V."]
+[Result "1/2-1/2"]
+[Annotator "\"ek\""]
+
+{Opening} 1. e4 e5 2. Nf3 Nc6 3. Bb5 {This opening is called the Ruy Lopez.} 3... a6
+4. Ba4 Nf6 5. O-O Be7 $1 (5... Nxe4 6. d4 (6. Re1 Nc5) b5 ; rest of line comment
+7. Bb3 d5) 6. Re1!? b5?! 7. Bb3 1/2-1/2`)
+
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Equal(t, "Fischer, Robert J.", game.Tags["White"])
+	assert.Equal(t, `"ek"`, game.Tags["Annotator"])
+	assert.Equal(t, chess.Draw, game.Result)
+	assert.Equal(t, []string{"Opening"}, game.Root.Comments)
+
+	assert.Equal(t,
+		[]string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Ba4", "Nf6", "O-O", "Be7", "Re1", "b5", "Bb3"},
+		game.MainLine())
+
+	// 3. Bb5
+	n := game.Root.Children[0].Children[0].Children[0].Children[0].Children[0]
+	assert.Equal(t, "Bb5", n.Move)
+	assert.Equal(t, []string{"This opening is called the Ruy Lopez."}, n.Comments)
+
+	// 5... Be7 and 5... Nxe4
+	n = n.Children[0].Children[0].Children[0].Children[0]
+	assert.Equal(t, "O-O", n.Move)
+	if assert.Len(t, n.Children, 2) {
+		assert.Equal(t, "Be7", n.Children[0].Move)
+		assert.Equal(t, []int{1}, n.Children[0].NAGs)
+
+		v := n.Children[1]
+		assert.Equal(t, "Nxe4", v.Move)
+		if assert.Len(t, v.Children, 2) {
+			assert.Equal(t, "d4", v.Children[0].Move)
+			assert.Equal(t, "Re1", v.Children[1].Move)
+			assert.Equal(t, "Nc5", v.Children[1].Children[0].Move)
+
+			b5 := v.Children[0].Children[0]
+			assert.Equal(t, "b5", b5.Move)
+			assert.Equal(t, []string{"rest of line comment"}, b5.Comments)
+			assert.Equal(t, "Bb3", b5.Children[0].Move)
+		}
+
+		// 6. Re1!? b5?!
+		re1 := n.Children[0].Children[0]
+		assert.Equal(t, []int{5}, re1.NAGs)
+		assert.Equal(t, []int{6}, re1.Children[0].NAGs)
+	}
+}
+
+func TestParsePGNMovetext(t *testing.T) {
+	t.Parallel()
+
+	for pgn, moves := range map[string][]string{
+		"e4 e5 Nf3":              {"e4", "e5", "Nf3"},
+		"1.e4 e5 2.Nf3":          {"e4", "e5", "Nf3"},
+		"1. e4 1... e5 2. Nf3 *": {"e4", "e5", "Nf3"},
+		"12...Nf6 13.e5+ 0-1":    {"Nf6", "e5+"},
+		"":                       nil,
+	} {
+		game, err := chess.ParsePGN(pgn)
+		if assert.NoError(t, err, pgn) {
+			assert.Equal(t, moves, game.MainLine(), pgn)
+		}
+	}
+}
+
+func TestParsePGNInvalid(t *testing.T) {
+	t.Parallel()
+
+	for pgn, message := range map[string]string{
+		`[Event "?"`:       "invalid PGN: invalid tag",
+		`[Event ?]`:        "invalid PGN: invalid tag",
+		`[Event "?]`:       "invalid PGN: unterminated string",
+		"1. e4 {comment":   "invalid PGN: unterminated comment",
+		"1. e4 (1. d4":     "invalid PGN: unterminated variation",
+		"1. e4 (1. d4 1-0": "invalid PGN: unterminated variation",
+		"1. e4 e5)":        "invalid PGN: unexpected )",
+		"(1. e4) e4":       "invalid PGN: variation before first move",
+		"$1 e4":            "invalid PGN: annotation before first move",
+		"1. e4 $ e5":       "invalid PGN: invalid NAG",
+		`1. e4 "string"`:   "invalid PGN: unexpected token: string",
+	} {
+		_, err := chess.ParsePGN(pgn)
+		assert.EqualError(t, err, message, pgn)
+	}
+}
+
+func TestNewGameFromPGN(t *testing.T) {
+	t.Parallel()
+
+	b, err := chess.NewGameFromPGN(`[Event "?"]
+
+1. e4 e5 (1... c5 2. Nf3) 2. Nf3 {main line} Nc6 *`)
+
+	if assert.NoError(t, err) {
+		assertPiece(t, b, "f3", chess.Knight, chess.Light)
+		assertPiece(t, b, "c6", chess.Knight, chess.Dark)
+		assertPiece(t, b, "e5", chess.Pawn, chess.Dark)
+		assertPiece(t, b, "c7", chess.Pawn, chess.Dark)
+	}
+
+	b, err = chess.NewGameFromPGN(`[SetUp "1"]
+[FEN "4k3/1P6/8/8/8/8/8/4K3 b - - 0 40"]
+
+40... Kd7 41. b8=Q`)
+
+	if assert.NoError(t, err) {
+		assertPiece(t, b, "b8", chess.Queen, chess.Light)
+		assertPiece(t, b, "d7", chess.King, chess.Dark)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"image/color"
 	"image/png"
 	"os"
+	"sync"
 
 	"golang.org/x/image/draw"
 )
@@ -17,21 +18,7 @@ type Piece struct {
 }
 
 func (p *Piece) String() string {
-	n := ""
-	switch p.Name {
-	case Pawn:
-		n = "pawn"
-	case Knight:
-		n = "knight"
-	case Bishop:
-		n = "bishop"
-	case Rook:
-		n = "rook"
-	case Queen:
-		n = "queen"
-	case King:
-		n = "king"
-	}
+	n := pieceNames[p.Name]
 
 	c := ""
 	switch p.Color {
@@ -55,6 +42,20 @@ const (
 	King   PieceName = "k"
 )
 
+var pieceNames = map[PieceName]string{
+	Pawn:   "pawn",
+	Knight: "knight",
+	Bishop: "bishop",
+	Rook:   "rook",
+	Queen:  "queen",
+	King:   "king",
+}
+
+// FullName returns the name of the piece like "knight".
+func (n PieceName) FullName() string {
+	return pieceNames[n]
+}
+
 type Color color.Color
 
 var (
@@ -64,6 +65,35 @@ var (
 	DarkGreen  Color = color.RGBA{170, 162, 58, 255}
 )
 
+// pieces never change so each piece image only needs to be loaded once
+var (
+	pieceCache      = map[pieceKey]*Piece{}
+	pieceCacheMutex sync.Mutex
+)
+
+type pieceKey struct {
+	name PieceName
+	side side
+}
+
+func cachedPiece(name PieceName, s side) (*Piece, error) {
+	pieceCacheMutex.Lock()
+	defer pieceCacheMutex.Unlock()
+
+	key := pieceKey{name, s}
+	if p, ok := pieceCache[key]; ok {
+		return p, nil
+	}
+
+	p, err := NewPiece(name, s.color())
+	if err != nil {
+		return nil, err
+	}
+	pieceCache[key] = p
+
+	return p, nil
+}
+
 func NewPiece(name PieceName, color Color) (*Piece, error) {
 	var (
 		colorSuffix string
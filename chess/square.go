@@ -1,6 +1,12 @@
 package chess
 
+import "fmt"
+
 type Square struct {
 	X int
 	Y int
 }
+
+func (s Square) String() string {
+	return fmt.Sprintf("%c%c", 'a'+s.X, '8'-s.Y)
+}
@@ -0,0 +1,124 @@
+package chess
+
+type Status int
+
+const (
+	Ongoing Status = iota
+	Checkmate
+	Stalemate
+	InsufficientMaterial
+	FiftyMoveRule
+	ThreefoldRepetition
+	KingInCenter
+	ThirdCheck
+	NoPiecesLeft
+)
+
+func (s Status) String() string {
+	switch s {
+	case Checkmate:
+		return "checkmate"
+	case Stalemate:
+		return "stalemate"
+	case InsufficientMaterial:
+		return "insufficient material"
+	case FiftyMoveRule:
+		return "fifty-move rule"
+	case ThreefoldRepetition:
+		return "threefold repetition"
+	case KingInCenter:
+		return "king in the center"
+	case ThirdCheck:
+		return "third check"
+	case NoPiecesLeft:
+		return "no pieces left"
+	default:
+		return "ongoing"
+	}
+}
+
+type Result string
+
+const (
+	NoResult  Result = "*"
+	WhiteWins Result = "1-0"
+	BlackWins Result = "0-1"
+	Draw      Result = "1/2-1/2"
+)
+
+// Status returns if the game is still ongoing or how it ended.
+func (b *Board) Status() Status {
+	status, _ := b.Variant().Outcome(b)
+	return status
+}
+
+// Result returns the result of the game in PGN notation.
+func (b *Board) Result() Result {
+	_, result := b.Variant().Outcome(b)
+	return result
+}
+
+// HalfmoveClock returns the number of half moves since the last capture or pawn move.
+func (b *Board) HalfmoveClock() int {
+	return b.halfmoveClock
+}
+
+// Occurrences returns how often the position with the given hash occurred in the game so far.
+func (b *Board) Occurrences(hash uint64) int {
+	return b.positions[hash]
+}
+
+// drawStatus returns how the game ended in a draw or Ongoing if it didn't.
+// Insufficient material is only checked if material is true since not all variants are won by checkmate.
+func (b *Board) drawStatus(material bool) Status {
+	if material && b.insufficientMaterial() {
+		return InsufficientMaterial
+	}
+
+	// 50 moves by each player without captures or pawn moves
+	if b.halfmoveClock >= 100 {
+		return FiftyMoveRule
+	}
+
+	if b.positions[b.repetitionHash()] >= 3 {
+		return ThreefoldRepetition
+	}
+
+	return Ongoing
+}
+
+// wins returns the result of a game the player with the given color won.
+func wins(c Color) Result {
+	if c == Light {
+		return WhiteWins
+	}
+	return BlackWins
+}
+
+// insufficientMaterial returns true if no sequence of legal moves can lead to checkmate.
+// This is the case for king vs king with at most one knight or any number of bishops on the same square color.
+func (b *Board) insufficientMaterial() bool {
+	var knights, bishops bitboard
+
+	for s := white; s <= black; s++ {
+		if b.pieces[s][pawnIndex]|b.pieces[s][rookIndex]|b.pieces[s][queenIndex] != 0 {
+			return false
+		}
+		knights |= b.pieces[s][knightIndex]
+		bishops |= b.pieces[s][bishopIndex]
+	}
+
+	if knights == 0 {
+		return bishops&lightSquares == 0 || bishops&^lightSquares == 0
+	}
+
+	return knights.count() == 1 && bishops == 0
+}
+
+// recordPosition counts the current position for threefold repetition.
+func (b *Board) recordPosition() {
+	if b.positions == nil {
+		b.positions = map[uint64]int{}
+	}
+	b.positions[b.repetitionHash()]++
+}
@@ -0,0 +1,74 @@
+package chess
+
+import (
+	"maps"
+	"slices"
+)
+
+// boardState is everything a move changes that can't be derived from the move itself.
+type boardState struct {
+	pieces         [2][6]bitboard
+	occupied       [2]bitboard
+	castling       castlingRights
+	enPassant      *Square
+	halfmoveClock  int
+	hash           uint64
+	moveIndicators []Tile
+}
+
+func (b *Board) saveState() {
+	b.history = append(b.history, boardState{
+		pieces:         b.pieces,
+		occupied:       b.occupied,
+		castling:       b.castling,
+		enPassant:      b.enPassant,
+		halfmoveClock:  b.halfmoveClock,
+		hash:           b.hash,
+		moveIndicators: b.moveIndicators,
+	})
+}
+
+// Undo takes back the last move.
+func (b *Board) Undo() error {
+	if len(b.history) == 0 {
+		return ErrNoMoveToUndo
+	}
+
+	// the current position no longer counts for repetitions
+	key := b.repetitionHash()
+	if b.positions[key]--; b.positions[key] <= 0 {
+		delete(b.positions, key)
+	}
+
+	s := b.history[len(b.history)-1]
+	b.history = b.history[:len(b.history)-1]
+
+	b.pieces = s.pieces
+	b.occupied = s.occupied
+	b.castling = s.castling
+	b.enPassant = s.enPassant
+	b.halfmoveClock = s.halfmoveClock
+	b.hash = s.hash
+	b.moveIndicators = s.moveIndicators
+
+	if b.turn == Light {
+		b.turn = Dark
+		b.fullmoveNumber--
+	} else {
+		b.turn = Light
+	}
+
+	b.Moves = b.Moves[:len(b.Moves)-1]
+
+	return nil
+}
+
+// Clone returns a copy of the board that can be changed without affecting the original.
+func (b *Board) Clone() *Board {
+	c := *b
+	c.positions = maps.Clone(b.positions)
+	c.Moves = slices.Clone(b.Moves)
+	c.moveIndicators = slices.Clone(b.moveIndicators)
+	c.history = slices.Clone(b.history)
+	return &c
+}
@@ -0,0 +1,84 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+// specialMoves contain captures, en passant, castling and a promotion.
+var specialMoves = []string{"e4", "d5", "e5", "f5", "exf6", "Nc6", "fxg7", "Be6", "Nf3", "Qd6", "Bc4", "O-O-O", "O-O", "h5", "gxh8=Q"}
+
+func TestBoardUndo(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assert.ErrorContains(t, b.Undo(), "no move to undo")
+
+	// castling, en passant, promotion and captures are restored exactly
+	for _, move := range specialMoves {
+		fen := b.FEN()
+		notation := b.AlgebraicNotation()
+
+		if _, err := b.Move(move); !assert.NoError(t, err, move) {
+			return
+		}
+
+		assert.NoError(t, b.Undo())
+		assert.Equal(t, fen, b.FEN(), "undo %s", move)
+		assert.Equal(t, notation, b.AlgebraicNotation(), "undo %s", move)
+
+		if _, err := b.Move(move); !assert.NoError(t, err, move) {
+			return
+		}
+	}
+
+	assertPiece(t, b, "h8", chess.Queen, chess.Light)
+	assert.NoError(t, b.Undo())
+	assertPiece(t, b, "g7", chess.Pawn, chess.Light)
+	assertPiece(t, b, "h8", chess.Rook, chess.Dark)
+}
+
+func TestBoardUndoRepetition(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "Nf3 Nf6 Ng1 Ng8")
+
+	// undone positions don't count for threefold repetition
+	assert.NoError(t, b.Undo())
+	assertParse(t, b, "Ng8")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	assertParse(t, b, "Nf3 Nf6 Ng1 Ng8")
+	assert.Equal(t, chess.ThreefoldRepetition, b.Status())
+
+	// game can continue after the last move was taken back
+	assert.NoError(t, b.Undo())
+	assert.Equal(t, chess.Ongoing, b.Status())
+	assertParse(t, b, "Nc6")
+}
+
+func TestBoardClone(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	assertParse(t, b, "e4 e5")
+
+	c := b.Clone()
+	assertParse(t, c, "Nf3 Nc6")
+	assert.NoError(t, c.Undo())
+
+	assert.Equal(t, "rnbqkbnr/pppp1ppp/8/4p3/4P3/8/PPPP1PPP/RNBQKBNR w KQkq e6 0 2", b.FEN())
+	assert.Len(t, b.Moves, 2)
+	assert.Len(t, c.Moves, 3)
+	assertNoPiece(t, b, "f3")
+	assertPiece(t, c, "f3", chess.Knight, chess.Light)
+
+	assert.NoError(t, b.Undo())
+	assertPiece(t, c, "e5", chess.Pawn, chess.Dark)
+}
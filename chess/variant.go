@@ -0,0 +1,96 @@
+package chess
+
+import (
+	"errors"
+	"strings"
+)
+
+// Variant changes the rules of standard chess.
+type Variant interface {
+	// Name returns the name of the variant like in the PGN Variant tag.
+	Name() string
+	// CheckMove returns an error with the reason if the player whose turn it is can't make the given move.
+	// The move already follows the movement rules of the pieces.
+	CheckMove(b *Board, m Move) error
+	// Promotions returns the pieces pawns can promote to.
+	Promotions() []PieceName
+	// RoyalKing returns true if kings can be in check.
+	RoyalKing() bool
+	// Outcome returns if the game is still ongoing or how it ended with the result.
+	Outcome(b *Board) (Status, Result)
+}
+
+var (
+	Standard      Variant = standard{}
+	KingOfTheHill Variant = kingOfTheHill{}
+	ThreeCheck    Variant = threeCheck{}
+	Antichess     Variant = antichess{}
+
+	Variants = []Variant{Standard, KingOfTheHill, ThreeCheck, Antichess}
+)
+
+// ParseVariant returns the variant with the given name.
+// Case, spaces and hyphens are ignored such that "threecheck" finds "Three-check".
+func ParseVariant(name string) (Variant, bool) {
+	normalize := func(s string) string {
+		return strings.NewReplacer(" ", "", "-", "").Replace(strings.ToLower(s))
+	}
+
+	for _, v := range Variants {
+		if normalize(v.Name()) == normalize(name) {
+			return v, true
+		}
+	}
+
+	return nil, false
+}
+
+// Variant returns the rules of the game.
+func (b *Board) Variant() Variant {
+	if b.variant == nil {
+		return Standard
+	}
+	return b.variant
+}
+
+// SetVariant changes the rules of the game. It should be called before the first move.
+func (b *Board) SetVariant(v Variant) {
+	b.variant = v
+}
+
+type standard struct{}
+
+func (standard) Name() string {
+	return "Standard"
+}
+
+func (standard) CheckMove(b *Board, m Move) error {
+	if !b.isLegal(m) {
+		return errors.New(b.checkReason(m))
+	}
+	return nil
+}
+
+func (standard) Promotions() []PieceName {
+	return promotions
+}
+
+func (standard) RoyalKing() bool {
+	return true
+}
+
+func (standard) Outcome(b *Board) (Status, Result) {
+	if len(b.LegalMoves()) == 0 {
+		if b.InCheck() {
+			// the player whose turn it is has been checkmated
+			return Checkmate, wins(b.us().other().color())
+		}
+		return Stalemate, Draw
+	}
+
+	if status := b.drawStatus(true); status != Ongoing {
+		return status, Draw
+	}
+
+	return Ongoing, NoResult
+}
@@ -0,0 +1,133 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVariant(t *testing.T) {
+	t.Parallel()
+
+	for name, expected := range map[string]chess.Variant{
+		"Standard":         chess.Standard,
+		"king of the hill": chess.KingOfTheHill,
+		"KingOfTheHill":    chess.KingOfTheHill,
+		"threecheck":       chess.ThreeCheck,
+		"Three-check":      chess.ThreeCheck,
+		"antichess":        chess.Antichess,
+	} {
+		v, ok := chess.ParseVariant(name)
+		assert.True(t, ok, name)
+		assert.Equal(t, expected, v, name)
+	}
+
+	_, ok := chess.ParseVariant("crazyhouse")
+	assert.False(t, ok)
+}
+
+func TestKingOfTheHill(t *testing.T) {
+	t.Parallel()
+
+	b := newVariantBoard(t, chess.KingOfTheHill, chess.StartFEN)
+
+	assertParse(t, b, "d3 e6 Kd2 Ke7 Kc3 Kd6")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	assertParse(t, b, "Kd4")
+	assert.Equal(t, chess.KingInCenter, b.Status())
+	assert.Equal(t, chess.WhiteWins, b.Result())
+	assertMoveError(t, b, "e5", "invalid move e5: game ended by king in the center")
+
+	// bare kings can still reach the center
+	b = newVariantBoard(t, chess.KingOfTheHill, "8/8/8/8/8/8/8/K6k w - - 0 1")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	// the king of the player to move can already be in the center
+	b = newVariantBoard(t, chess.KingOfTheHill, "7k/8/8/8/3K4/8/8/8 w - - 0 1")
+	assert.Equal(t, chess.KingInCenter, b.Status())
+	assert.Equal(t, chess.WhiteWins, b.Result())
+}
+
+func TestThreeCheck(t *testing.T) {
+	t.Parallel()
+
+	b := newVariantBoard(t, chess.ThreeCheck, chess.StartFEN)
+
+	assertParse(t, b, "e4 e5 Qh5 Nc6 Qxf7+ Kxf7 Bc4+ Ke8")
+	assert.Equal(t, chess.Ongoing, b.Status())
+
+	assertParse(t, b, "Bf7+")
+	assert.Equal(t, chess.ThirdCheck, b.Status())
+	assert.Equal(t, chess.WhiteWins, b.Result())
+
+	pgn := b.PGN(nil)
+	assert.Contains(t, pgn, `[Variant "Three-check"]`)
+
+	replay, err := chess.NewGameFromPGN(pgn)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, chess.ThreeCheck, replay.Variant())
+	assert.Equal(t, chess.ThirdCheck, replay.Status())
+}
+
+func TestAntichess(t *testing.T) {
+	t.Parallel()
+
+	b := newVariantBoard(t, chess.Antichess, chess.StartFEN)
+
+	assertParse(t, b, "e3 b5")
+
+	// captures are compulsory
+	moves := b.LegalMoves()
+	assert.Len(t, moves, 1)
+	assertLegalMove(t, moves, "f1", "b5")
+	assertMoveError(t, b, "Qh5", "invalid move Qh5: captures are compulsory in antichess")
+
+	// kings can be captured and the player without pieces wins
+	b = newVariantBoard(t, chess.Antichess, "7k/8/8/8/8/8/8/K5R1 b - - 0 1")
+	assertParse(t, b, "Kg8 Rxg8")
+	assert.Equal(t, chess.NoPiecesLeft, b.Status())
+	assert.Equal(t, chess.BlackWins, b.Result())
+
+	// kings are no royal pieces so there are no checks
+	b = newVariantBoard(t, chess.Antichess, "4k3/8/8/8/8/8/8/R3K3 w - - 0 1")
+	m, err := b.Move("Ra8")
+	assert.NoError(t, err)
+	assert.Equal(t, "Ra8", m.SAN)
+	assert.False(t, m.Check)
+	assert.False(t, m.Checkmate)
+
+	// pawns can promote to kings
+	b = newVariantBoard(t, chess.Antichess, "7k/4P3/8/8/8/8/8/K7 w - - 0 1")
+	var uci []string
+	for _, m := range b.LegalMoves() {
+		uci = append(uci, m.UCI())
+	}
+	assert.Contains(t, uci, "e7e8k")
+	m, err = b.Move("e8=K")
+	assert.NoError(t, err)
+	assert.Equal(t, "e8=K", m.SAN)
+	assertPiece(t, b, "e8", chess.King, chess.Light)
+
+	b = newVariantBoard(t, chess.Antichess, "7k/4P3/8/8/8/8/8/K7 w - - 0 1")
+	_, err = b.Move("e7e8k")
+	assert.NoError(t, err)
+	assertPiece(t, b, "e8", chess.King, chess.Light)
+
+	// but not in standard chess
+	b = newVariantBoard(t, chess.Standard, "7k/4P3/8/8/8/8/8/K7 w - - 0 1")
+	assertMoveError(t, b, "e8=K", "invalid move e8=K: invalid promotion: K")
+	assertMoveError(t, b, "e7e8k", "invalid move e7e8k: invalid promotion: k")
+}
+
+func newVariantBoard(t *testing.T, v chess.Variant, fen string) *chess.Board {
+	b, err := chess.NewBoardFromFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b.SetVariant(v)
+	return b
+}
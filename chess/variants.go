@@ -0,0 +1,131 @@
+package chess
+
+import "errors"
+
+// d4, e4, d5 and e5
+const centerSquares bitboard = 1<<27 | 1<<28 | 1<<35 | 1<<36
+
+// checksToWin is the number of checks that win a game of Three-check.
+const checksToWin = 3
+
+// kingOfTheHill is won by moving the king to the center.
+type kingOfTheHill struct{}
+
+func (kingOfTheHill) Name() string {
+	return "King of the Hill"
+}
+
+func (kingOfTheHill) CheckMove(b *Board, m Move) error {
+	return Standard.CheckMove(b, m)
+}
+
+func (kingOfTheHill) Promotions() []PieceName {
+	return Standard.Promotions()
+}
+
+func (kingOfTheHill) RoyalKing() bool {
+	return true
+}
+
+func (kingOfTheHill) Outcome(b *Board) (Status, Result) {
+	// the player who moved last is checked first since games can also start with a king in the center
+	for _, s := range []side{b.us().other(), b.us()} {
+		if b.pieces[s][kingIndex]&centerSquares != 0 {
+			return KingInCenter, wins(s.color())
+		}
+	}
+
+	status, result := Standard.Outcome(b)
+	if status == InsufficientMaterial {
+		// kings can always walk to the center
+		if status = b.drawStatus(false); status == Ongoing {
+			result = NoResult
+		}
+	}
+	return status, result
+}
+
+// threeCheck is won by giving check three times.
+type threeCheck struct{}
+
+func (threeCheck) Name() string {
+	return "Three-check"
+}
+
+func (threeCheck) CheckMove(b *Board, m Move) error {
+	return Standard.CheckMove(b, m)
+}
+
+func (threeCheck) Promotions() []PieceName {
+	return Standard.Promotions()
+}
+
+func (threeCheck) RoyalKing() bool {
+	return true
+}
+
+func (threeCheck) Outcome(b *Board) (Status, Result) {
+	// only the player who moved last can have given the last check
+	checks := 0
+	for i := len(b.Moves) - 1; i >= 0; i -= 2 {
+		if b.Moves[i].Check {
+			checks++
+		}
+	}
+	if checks >= checksToWin {
+		return ThirdCheck, wins(b.us().other().color())
+	}
+
+	return Standard.Outcome(b)
+}
+
+// antichess is won by losing all pieces or by having no legal moves.
+// The king is no royal piece and captures are compulsory.
+type antichess struct{}
+
+func (antichess) Name() string {
+	return "Antichess"
+}
+
+func (antichess) CheckMove(b *Board, m Move) error {
+	if m.Castle != NoCastle {
+		return errors.New("castling is not allowed in antichess")
+	}
+
+	if m.Captured == "" {
+		for _, o := range b.pseudoLegalMoves() {
+			if o.Captured != "" {
+				return errors.New("captures are compulsory in antichess")
+			}
+		}
+	}
+
+	return nil
+}
+
+// Promotions includes the king since it's no royal piece.
+func (antichess) Promotions() []PieceName {
+	return append(Standard.Promotions(), King)
+}
+
+// RoyalKing returns false since kings can be captured like any other piece.
+func (antichess) RoyalKing() bool {
+	return false
+}
+
+func (antichess) Outcome(b *Board) (Status, Result) {
+	if b.occupied[b.us()] == 0 {
+		return NoPiecesLeft, wins(b.turn)
+	}
+
+	if len(b.LegalMoves()) == 0 {
+		return Stalemate, wins(b.turn)
+	}
+
+	// even bare kings can still capture each other
+	if status := b.drawStatus(false); status != Ongoing {
+		return status, Draw
+	}
+
+	return Ongoing, NoResult
+}
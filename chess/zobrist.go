@@ -0,0 +1,109 @@
+package chess
+
+import "slices"
+
+// zobristKeys has a random key for each feature of a position.
+// The hash of a position is the XOR of the keys of all its features.
+// See https://www.chessprogramming.org/Zobrist_Hashing
+type zobristKeys struct {
+	pieces    [2][6][64]uint64
+	castling  [16]uint64
+	enPassant [8]uint64
+	// included if black is to move
+	turn uint64
+}
+
+var zobrist = newZobristKeys()
+
+func newZobristKeys() *zobristKeys {
+	var (
+		keys = &zobristKeys{}
+		// keys are generated with a fixed seed such that hashes are the same on every run
+		seed uint64 = 0x9e3779b97f4a7c15
+	)
+
+	next := func() uint64 {
+		// splitmix64
+		seed += 0x9e3779b97f4a7c15
+		z := seed
+		z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+		z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+		return z ^ (z >> 31)
+	}
+
+	for s := range keys.pieces {
+		for p := range keys.pieces[s] {
+			for sq := range keys.pieces[s][p] {
+				keys.pieces[s][p][sq] = next()
+			}
+		}
+	}
+	for i := range keys.castling {
+		keys.castling[i] = next()
+	}
+	for i := range keys.enPassant {
+		keys.enPassant[i] = next()
+	}
+	keys.turn = next()
+
+	return keys
+}
+
+// Hash returns the Zobrist hash of the position.
+// Positions with the same pieces on the same squares, the same player to move,
+// the same castling rights and the same en passant captures have the same hash.
+func (b *Board) Hash() uint64 {
+	return b.hash
+}
+
+// computeHash calculates the hash from scratch.
+// After that, it's updated incrementally whenever the position changes.
+func (b *Board) computeHash() uint64 {
+	var hash uint64
+
+	for s := white; s <= black; s++ {
+		for p := range pieceOrder {
+			for bb := b.pieces[s][p]; bb != 0; bb &= bb - 1 {
+				hash ^= zobrist.pieces[s][p][bb.lowest()]
+			}
+		}
+	}
+
+	hash ^= zobrist.castling[b.castling]
+
+	if b.enPassantHashed() {
+		hash ^= zobrist.enPassant[b.enPassant.X]
+	}
+
+	if b.turn == Dark {
+		hash ^= zobrist.turn
+	}
+
+	return hash
+}
+
+// enPassantHashed returns true if a pawn of the player whose turn it is attacks the en passant square.
+// Otherwise, the en passant square makes no difference and is not included in the hash.
+func (b *Board) enPassantHashed() bool {
+	if b.enPassant == nil {
+		return false
+	}
+
+	us := b.us()
+	// our pawns attack the square from where pawns of the other side on the square would attack
+	return pawnAttacks[us.other()][b.enPassant.index()]&b.pieces[us][pawnIndex] != 0
+}
+
+// repetitionHash identifies positions for repetitions.
+// Positions are the same if the same pieces are on the same squares, the same player is to move
+// and the same moves are possible, including castling and en passant.
+func (b *Board) repetitionHash() uint64 {
+	hash := b.hash
+
+	// en passant only matters if the capture is legal
+	if b.enPassantHashed() && !slices.ContainsFunc(b.LegalMoves(), func(m Move) bool { return m.EnPassant }) {
+		hash ^= zobrist.enPassant[b.enPassant.X]
+	}
+
+	return hash
+}
@@ -0,0 +1,96 @@
+package chess_test
+
+import (
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBoardHashTransposition(t *testing.T) {
+	t.Parallel()
+
+	b1 := chess.NewBoard()
+	b2 := chess.NewBoard()
+
+	assert.Equal(t, b1.Hash(), b2.Hash())
+
+	assertParse(t, b1, "Nf3 Nf6 Nc3 Nc6")
+	assertParse(t, b2, "Nc3 Nc6 Nf3 Nf6")
+	assert.Equal(t, b1.Hash(), b2.Hash())
+
+	// en passant squares that can't be captured don't matter
+	b1 = chess.NewBoard()
+	b2 = chess.NewBoard()
+	assertParse(t, b1, "e4 e6 d4")
+	assertParse(t, b2, "d4 e6 e4")
+	assert.NotEqual(t, b1.FEN(), b2.FEN())
+	assert.Equal(t, b1.Hash(), b2.Hash())
+
+	// the hash only depends on the position and not on the moves
+	b3, err := chess.NewBoardFromFEN(b1.FEN())
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, b1.Hash(), b3.Hash())
+}
+
+func TestBoardHashDifferences(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+	start := b.Hash()
+
+	// same position but black to move
+	assertParse(t, b, "Nf3 Nf6 Ng1 Ng8")
+	assert.Equal(t, start, b.Hash())
+	assertParse(t, b, "Nf3 Nf6 Ng1")
+	assert.NotEqual(t, start, b.Hash())
+
+	// same position but castling rights were lost
+	b1 := chess.NewBoard()
+	b2 := chess.NewBoard()
+	assertParse(t, b1, "e4 e5 Nf3 Nf6 Ng1 Ng8")
+	assertParse(t, b2, "e4 e5 Ke2 Ke7 Ke1 Ke8")
+	assert.NotEqual(t, b1.Hash(), b2.Hash())
+
+	// en passant square that can be captured
+	b, err := chess.NewBoardFromFEN("4k3/8/8/3pP3/8/8/8/4K3 w - d6 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	noEnPassant, err := chess.NewBoardFromFEN("4k3/8/8/3pP3/8/8/8/4K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, b.Hash(), noEnPassant.Hash())
+}
+
+func TestBoardHashIncremental(t *testing.T) {
+	t.Parallel()
+
+	b := chess.NewBoard()
+
+	// captures, en passant, castling and promotions update the hash like a new board from the same position
+	moves := specialMoves
+	hashes := []uint64{b.Hash()}
+	for _, move := range moves {
+		if _, err := b.Move(move); !assert.NoError(t, err, move) {
+			return
+		}
+
+		fromFEN, err := chess.NewBoardFromFEN(b.FEN())
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, fromFEN.Hash(), b.Hash(), "hash after %s", move)
+
+		hashes = append(hashes, b.Hash())
+	}
+
+	// undo restores the previous hashes
+	for i := len(moves) - 1; i >= 0; i-- {
+		assert.NoError(t, b.Undo())
+		assert.Equal(t, hashes[i], b.Hash(), "hash after undoing %s", moves[i])
+	}
+}
@@ -4,16 +4,17 @@ import (
 	"database/sql"
 	"errors"
 	"log"
+	"sync"
+	"time"
 
 	sn "github.com/ekzyis/snappy"
 	_ "github.com/mattn/go-sqlite3"
 )
 
-var (
-	db = getDb()
-)
+// db is opened on first use such that importing the package has no side effects
+var db = sync.OnceValue(openDb)
 
-func getDb() *sql.DB {
+func openDb() *sql.DB {
 	var (
 		db  *sql.DB
 		err error
@@ -37,7 +38,15 @@ func migrate(db *sql.DB) error {
 			parent_id INTEGER REFERENCES items(id),
 			created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
-		)
+		);
+		CREATE TABLE IF NOT EXISTS users (
+			id INTEGER PRIMARY KEY,
+			name TEXT NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS games (
+			id INTEGER PRIMARY KEY REFERENCES items(id),
+			variant TEXT NOT NULL
+		);
 	`)
 
 	return err
@@ -49,7 +58,7 @@ func ItemHasReply(parentId int, userId int) (bool, error) {
 		err   error
 	)
 
-	if err = db.QueryRow(`SELECT COUNT(1) FROM items WHERE parent_id = ? AND user_id = ?`, parentId, userId).Scan(&count); err != nil {
+	if err = db().QueryRow(`SELECT COUNT(1) FROM items WHERE parent_id = ? AND user_id = ?`, parentId, userId).Scan(&count); err != nil {
 		return true, err
 	}
 
@@ -58,7 +67,7 @@ func ItemHasReply(parentId int, userId int) (bool, error) {
 	}
 
 	// check if parent already exists, this means we ignored it
-	if err = db.QueryRow(`SELECT COUNT(1) FROM items WHERE id = ?`, parentId).Scan(&count); err != nil {
+	if err = db().QueryRow(`SELECT COUNT(1) FROM items WHERE id = ?`, parentId).Scan(&count); err != nil {
 		return true, err
 	}
 
@@ -66,13 +75,23 @@ func ItemHasReply(parentId int, userId int) (bool, error) {
 }
 
 func InsertItem(item *sn.Item) error {
-	if _, err := db.Exec(``+
+	if _, err := db().Exec(``+
 		`INSERT INTO items(id, user_id, text, parent_id) VALUES (?, ?, ?, NULLIF(?, 0)) `+
 		`ON CONFLICT DO UPDATE SET text = EXCLUDED.text, updated_at = CURRENT_TIMESTAMP`,
 		item.Id, item.User.Id, item.Text, item.ParentId); err != nil {
 		return err
 	}
 
+	// remember user names so we can show them in game records
+	if item.User.Name != "" {
+		if _, err := db().Exec(``+
+			`INSERT INTO users(id, name) VALUES (?, ?) `+
+			`ON CONFLICT DO UPDATE SET name = EXCLUDED.name`,
+			item.User.Id, item.User.Name); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -86,19 +105,46 @@ func GetThread(id int) ([]sn.Item, error) {
 	item.ParentId = id
 
 	for item.ParentId > 0 {
-		// TODO: can't select created_at because sqlite3 doesn't support timestamps natively
+		// sqlite3 doesn't support timestamps natively so we select created_at as unix time
 		// see https://github.com/mattn/go-sqlite3/issues/142
-		if err = db.QueryRow(
-			`SELECT id, user_id, text, COALESCE(parent_id, 0) FROM items WHERE id = ?`, item.ParentId).
-			Scan(&item.Id, &item.User.Id, &item.Text, &item.ParentId); err != nil {
+		var createdAt int64
+		if err = db().QueryRow(``+
+			`SELECT i.id, i.user_id, COALESCE(u.name, ''), i.text, COALESCE(i.parent_id, 0), CAST(strftime('%s', i.created_at) AS INTEGER) `+
+			`FROM items i LEFT JOIN users u ON u.id = i.user_id WHERE i.id = ?`, item.ParentId).
+			Scan(&item.Id, &item.User.Id, &item.User.Name, &item.Text, &item.ParentId, &createdAt); err != nil {
 			if err == sql.ErrNoRows {
 				return nil, errors.New("item not found in db")
 			}
 			return nil, err
 		}
+		item.CreatedAt = time.Unix(createdAt, 0).UTC()
 
 		items = append([]sn.Item{item}, items...)
 	}
 
 	return items, nil
 }
+
+// InsertGame remembers the rules of the game started by the item with the given id.
+func InsertGame(id int, variant string) error {
+	_, err := db().Exec(``+
+		`INSERT INTO games(id, variant) VALUES (?, ?) `+
+		`ON CONFLICT DO UPDATE SET variant = EXCLUDED.variant`,
+		id, variant)
+	return err
+}
+
+// GetVariant returns the variant of the game started by the item with the given id.
+// It returns an empty string for games that were started before variants were recorded.
+func GetVariant(id int) (string, error) {
+	var variant string
+
+	if err := db().QueryRow(`SELECT variant FROM games WHERE id = ?`, id).Scan(&variant); err != nil {
+		if err == sql.ErrNoRows {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return variant, nil
+}
@@ -0,0 +1,143 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+)
+
+type Judgment string
+
+const (
+	Good       Judgment = ""
+	Inaccuracy Judgment = "?!"
+	Mistake    Judgment = "?"
+	Blunder    Judgment = "??"
+)
+
+// minimum centipawns lost compared to the best move for each judgment
+const (
+	inaccuracyLoss = 50
+	mistakeLoss    = 100
+	blunderLoss    = 300
+	// scores beyond a decisive advantage don't matter for judging moves
+	decisiveScore = 1000
+)
+
+// MoveAnalysis is the judgment of a move compared to the best move the engine found.
+type MoveAnalysis struct {
+	Move chess.Move
+	// best move in the position before the move
+	Best chess.Move
+	// centipawns lost compared to the best move
+	Loss     int
+	Judgment Judgment
+}
+
+func (j Judgment) String() string {
+	switch j {
+	case Inaccuracy:
+		return "Inaccuracy"
+	case Mistake:
+		return "Mistake"
+	case Blunder:
+		return "Blunder"
+	default:
+		return "Good move"
+	}
+}
+
+// Annotation returns the annotation of the move for PGN like "?? Nf3 was best."
+func (a MoveAnalysis) Annotation() chess.Annotation {
+	if a.Judgment == Good {
+		return chess.Annotation{}
+	}
+	return chess.Annotation{
+		Symbol:  string(a.Judgment),
+		Comment: fmt.Sprintf("%s. %s was best.", a.Judgment, a.Best.SAN),
+	}
+}
+
+// Analyze evaluates each position of the game and judges every move by how much worse it is than the best move.
+// The total time of the analysis is split evenly across the positions.
+func (e *Engine) Analyze(b *chess.Board) ([]MoveAnalysis, error) {
+	var (
+		analysis = make([]MoveAnalysis, len(b.Moves))
+		// scores of all positions from the point of view of the player to move
+		scores   = make([]int, len(b.Moves)+1)
+		best     = make([]chess.Move, len(b.Moves))
+		limits   = e.Limits
+		deadline = time.Now().Add(limits.TotalTime)
+		replay   *chess.Board
+		err      error
+	)
+
+	// restore the limits of single searches
+	defer func() { e.Limits = limits }()
+
+	if replay, err = chess.NewBoardFromFEN(b.InitialFEN()); err != nil {
+		return nil, err
+	}
+
+	for i := 0; i <= len(b.Moves); i++ {
+		if status := replay.Status(); status != chess.Ongoing {
+			// the game is over so there is nothing to search; drawn positions score 0
+			if status == chess.Checkmate {
+				scores[i] = -decisiveScore
+			}
+		} else {
+			if limits.TotalTime > 0 {
+				// positions searched faster than their share leave more time for the remaining positions
+				e.Limits.MoveTime = max(time.Until(deadline)/time.Duration(len(b.Moves)+1-i), time.Millisecond)
+				if limits.MoveTime > 0 {
+					e.Limits.MoveTime = min(e.Limits.MoveTime, limits.MoveTime)
+				}
+			}
+
+			var res Result
+			if res, err = e.Search(replay); err != nil {
+				return nil, err
+			}
+			scores[i] = res.Score
+			if i < len(b.Moves) {
+				best[i] = res.Move
+				best[i].SAN = replay.SAN(res.Move)
+			}
+		}
+
+		if i == len(b.Moves) {
+			break
+		}
+
+		if _, err = replay.Move(b.Moves[i].UCI()); err != nil {
+			return nil, err
+		}
+	}
+
+	for i, m := range b.Moves {
+		a := MoveAnalysis{Move: m, Best: best[i]}
+
+		if m.UCI() != a.Best.UCI() {
+			// the score after the move is from the point of view of the opponent
+			a.Loss = max(0, clamp(scores[i])+clamp(scores[i+1]))
+		}
+
+		switch {
+		case a.Loss >= blunderLoss:
+			a.Judgment = Blunder
+		case a.Loss >= mistakeLoss:
+			a.Judgment = Mistake
+		case a.Loss >= inaccuracyLoss:
+			a.Judgment = Inaccuracy
+		}
+
+		analysis[i] = a
+	}
+
+	return analysis, nil
+}
+
+func clamp(score int) int {
+	return min(max(score, -decisiveScore), decisiveScore)
+}
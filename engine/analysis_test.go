@@ -0,0 +1,85 @@
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/ekzyis/chessbot/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAnalyze(t *testing.T) {
+	t.Parallel()
+
+	e := &engine.Engine{Limits: engine.Limits{Depth: 2, MoveTime: time.Second}}
+
+	b := chess.NewBoard()
+	for _, m := range []string{"e4", "e5", "Qh5", "Nc6", "Bc4", "Nf6", "Qxf7#"} {
+		if _, err := b.Move(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	analysis, err := e.Analyze(b)
+	if !assert.NoError(t, err) || !assert.Len(t, analysis, 7) {
+		return
+	}
+
+	// Nf6 allows mate
+	a := analysis[5]
+	assert.Equal(t, "Nf6", a.Move.SAN)
+	assert.Equal(t, engine.Blunder, a.Judgment)
+	assert.NotEqual(t, "Nf6", a.Best.SAN)
+	assert.Equal(t, "??", a.Annotation().Symbol)
+	assert.Equal(t, "Blunder. "+a.Best.SAN+" was best.", a.Annotation().Comment)
+
+	// Qxf7# was the best move
+	a = analysis[6]
+	assert.Equal(t, "Qxf7#", a.Best.SAN)
+	assert.Equal(t, 0, a.Loss)
+	assert.Equal(t, engine.Good, a.Judgment)
+	assert.Equal(t, chess.Annotation{}, a.Annotation())
+}
+
+func TestAnalyzeTotalTime(t *testing.T) {
+	t.Parallel()
+
+	limits := engine.Limits{Depth: 20, MoveTime: time.Second, TotalTime: 500 * time.Millisecond}
+	e := &engine.Engine{Limits: limits}
+
+	b := chess.NewBoard()
+	for _, m := range []string{"e4", "e5", "Nf3", "Nc6", "Bb5", "a6", "Ba4", "Nf6", "O-O", "Be7", "Re1", "b5", "Bb3", "d6"} {
+		if _, err := b.Move(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// all positions share the total time instead of getting the time of a single search each
+	start := time.Now()
+	analysis, err := e.Analyze(b)
+	assert.NoError(t, err)
+	assert.Len(t, analysis, 14)
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Equal(t, limits, e.Limits)
+}
+
+func TestAnalyzeDraw(t *testing.T) {
+	t.Parallel()
+
+	e := &engine.Engine{Limits: engine.Limits{Depth: 2, MoveTime: time.Second}}
+
+	// Qh3 draws by the fifty-move rule instead of mating with Qh8#
+	b := newBoard(t, "k7/8/1K6/8/8/8/7Q/8 w - - 99 1")
+	if _, err := b.Move("Qh3"); err != nil {
+		t.Fatal(err)
+	}
+	assert.Equal(t, chess.FiftyMoveRule, b.Status())
+
+	analysis, err := e.Analyze(b)
+	if !assert.NoError(t, err) || !assert.Len(t, analysis, 1) {
+		return
+	}
+	assert.Equal(t, "Qh8#", analysis[0].Best.SAN)
+	assert.Equal(t, engine.Blunder, analysis[0].Judgment)
+}
@@ -0,0 +1,322 @@
+// Package engine implements a chess engine on top of chess.Board.
+// It searches with alpha-beta pruning and iterative deepening and evaluates positions
+// by material and piece-square tables. Captures at the end of the search are resolved with a quiescence search.
+package engine
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+)
+
+const (
+	MinLevel     = 1
+	MaxLevel     = 5
+	DefaultLevel = 3
+
+	// score of a checkmate; mates in fewer moves score higher
+	mateScore = 100000
+	// scores beyond this bound are forced mates
+	mateBound = mateScore - 1000
+	infinity  = 1000000
+	// how many nodes to search between time checks
+	checkInterval = 1024
+	// search time per level; replies wait for the engine so even the highest level must be quick
+	levelTime = 400 * time.Millisecond
+)
+
+// Limits restrict how long the engine searches.
+type Limits struct {
+	// maximum search depth in half moves
+	Depth int
+	// maximum search time; zero means no limit
+	MoveTime time.Duration
+	// maximum time for all searches of an analysis; zero means no limit
+	TotalTime time.Duration
+}
+
+// Result is the outcome of a search.
+type Result struct {
+	Move chess.Move
+	// score in centipawns from the point of view of the player to move
+	Score int
+	// depth of the last completed iteration
+	Depth int
+	Nodes int
+	// principal variation: the best line of play found, starting with Move
+	PV []chess.Move
+}
+
+type Engine struct {
+	Limits Limits
+
+	nodes    int
+	deadline time.Time
+	// game of the search to look up positions that occurred before the search
+	game *chess.Board
+	// hashes of the positions between the root and the current node
+	path []uint64
+	// the first iteration is never stopped such that there always is a move
+	stoppable bool
+	stopped   bool
+}
+
+// New returns an engine that plays at the given level.
+// Higher levels search deeper and longer but no more than two seconds.
+func New(level int) (*Engine, error) {
+	if level < MinLevel || level > MaxLevel {
+		return nil, fmt.Errorf("invalid level %d: must be between %d and %d", level, MinLevel, MaxLevel)
+	}
+
+	return &Engine{Limits: Limits{Depth: level, MoveTime: time.Duration(level) * levelTime}}, nil
+}
+
+// BestMove returns the best move the engine found for the player whose turn it is.
+// While the game is in the opening book, it plays a book move instead
+// that is chosen at random by the weight of the book lines.
+func (e *Engine) BestMove(b *chess.Board) (chess.Move, error) {
+	if moves := b.BookMoves(); len(moves) > 0 {
+		return pickBookMove(moves), nil
+	}
+
+	res, err := e.Search(b)
+	if err != nil {
+		return chess.Move{}, err
+	}
+	return res.Move, nil
+}
+
+// pickBookMove returns a random book move where moves with higher weight are more likely.
+func pickBookMove(moves []chess.BookMove) chess.Move {
+	total := 0
+	for _, m := range moves {
+		total += m.Weight
+	}
+
+	n := rand.Intn(total)
+	for _, m := range moves {
+		if n < m.Weight {
+			return m.Move
+		}
+		n -= m.Weight
+	}
+
+	return moves[0].Move
+}
+
+// Search searches the position with increasing depth until the depth or time limit is reached.
+// If time runs out, the result of the last completed depth is returned.
+func (e *Engine) Search(b *chess.Board) (Result, error) {
+	var (
+		moves = b.LegalMoves()
+		res   Result
+	)
+
+	if len(moves) == 0 {
+		return Result{}, errors.New("no legal moves")
+	}
+
+	e.nodes = 0
+	e.stopped = false
+	e.game = b
+	e.path = e.path[:0]
+	e.deadline = time.Time{}
+	if e.Limits.MoveTime > 0 {
+		e.deadline = time.Now().Add(e.Limits.MoveTime)
+	}
+
+	orderMoves(moves)
+	res.Move = moves[0]
+
+	maxDepth := e.Limits.Depth
+	if maxDepth <= 0 {
+		maxDepth = MaxLevel
+	}
+
+	for depth := 1; depth <= maxDepth; depth++ {
+		e.stoppable = depth > 1
+
+		var (
+			alpha = -infinity
+			best  chess.Move
+			pv    []chess.Move
+		)
+
+		for _, m := range moves {
+			var line []chess.Move
+			score := -e.negamax(b.After(m), depth-1, 1, -infinity, -alpha, &line)
+			if e.stopped {
+				break
+			}
+			if score > alpha {
+				alpha = score
+				best = m
+				pv = append([]chess.Move{m}, line...)
+			}
+		}
+
+		if e.stopped {
+			break
+		}
+
+		res = Result{Move: best, Score: alpha, Depth: depth, PV: pv}
+
+		// search best move first in next iteration
+		moveToFront(moves, best)
+
+		if alpha > mateBound || alpha < -mateBound {
+			// no need to search deeper if a forced mate was found
+			break
+		}
+	}
+
+	res.Nodes = e.nodes
+
+	return res, nil
+}
+
+func (e *Engine) negamax(b *chess.Board, depth int, ply int, alpha int, beta int, pv *[]chess.Move) int {
+	if e.stop() {
+		return 0
+	}
+
+	if e.drawn(b) {
+		return 0
+	}
+
+	if depth == 0 {
+		return e.quiesce(b, ply, alpha, beta)
+	}
+
+	moves := b.LegalMoves()
+	if len(moves) == 0 {
+		if b.InCheck() {
+			return -mateScore + ply
+		}
+		// stalemate
+		return 0
+	}
+
+	orderMoves(moves)
+
+	e.path = append(e.path, b.Hash())
+	defer func() { e.path = e.path[:len(e.path)-1] }()
+
+	for _, m := range moves {
+		var line []chess.Move
+		score := -e.negamax(b.After(m), depth-1, ply+1, -beta, -alpha, &line)
+		if e.stopped {
+			return 0
+		}
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+			*pv = append([]chess.Move{m}, line...)
+		}
+	}
+
+	return alpha
+}
+
+// drawn returns true if the position repeats a position of the game or of the current line
+// or if the fifty-move rule applies. The search scores these positions as 0 such that
+// it avoids repetitions when winning and aims for them when losing.
+func (e *Engine) drawn(b *chess.Board) bool {
+	if b.HalfmoveClock() >= 100 && len(b.LegalMoves()) > 0 {
+		// checkmate on the last move still wins
+		return true
+	}
+
+	hash := b.Hash()
+	return e.game.Occurrences(hash) > 0 || slices.Contains(e.path, hash)
+}
+
+// quiesce only searches captures and promotions until the position is quiet
+// such that the evaluation does not miss pieces that are about to be captured.
+func (e *Engine) quiesce(b *chess.Board, ply int, alpha int, beta int) int {
+	if e.stop() {
+		return 0
+	}
+
+	moves := b.LegalMoves()
+	if len(moves) == 0 {
+		if b.InCheck() {
+			return -mateScore + ply
+		}
+		return 0
+	}
+
+	// the player to move can usually do at least as good as the current position
+	standPat := Evaluate(b)
+	if standPat >= beta {
+		return beta
+	}
+	if standPat > alpha {
+		alpha = standPat
+	}
+
+	var captures []chess.Move
+	for _, m := range moves {
+		if m.Captured != "" || m.Promotion != "" {
+			captures = append(captures, m)
+		}
+	}
+	orderMoves(captures)
+
+	for _, m := range captures {
+		score := -e.quiesce(b.After(m), ply+1, -beta, -alpha)
+		if e.stopped {
+			return 0
+		}
+		if score >= beta {
+			return beta
+		}
+		if score > alpha {
+			alpha = score
+		}
+	}
+
+	return alpha
+}
+
+// stop counts the node and returns true if the search ran out of time.
+func (e *Engine) stop() bool {
+	e.nodes++
+	if e.stoppable && !e.stopped && !e.deadline.IsZero() && e.nodes%checkInterval == 0 && time.Now().After(e.deadline) {
+		e.stopped = true
+	}
+	return e.stopped
+}
+
+// orderMoves sorts moves such that promotions and captures of valuable pieces
+// with cheap pieces are searched first since they most likely cause cutoffs.
+func orderMoves(moves []chess.Move) {
+	priority := func(m chess.Move) int {
+		p := pieceValues[m.Promotion]
+		if m.Captured != "" {
+			p += 10*pieceValues[m.Captured] - pieceValues[m.Piece]
+		}
+		return p
+	}
+
+	sort.SliceStable(moves, func(i, j int) bool {
+		return priority(moves[i]) > priority(moves[j])
+	})
+}
+
+func moveToFront(moves []chess.Move, m chess.Move) {
+	for i := range moves {
+		if moves[i] == m {
+			copy(moves[1:i+1], moves[:i])
+			moves[0] = m
+			return
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package engine_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/ekzyis/chessbot/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func newBoard(t *testing.T, fen string) *chess.Board {
+	b, err := chess.NewBoardFromFEN(fen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestEvaluate(t *testing.T) {
+	t.Parallel()
+
+	assert.Equal(t, 0, engine.Evaluate(chess.NewBoard()))
+
+	// white is a queen up
+	b := newBoard(t, "rnb1kbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+	assert.Greater(t, engine.Evaluate(b), 800)
+
+	// same position from black's point of view
+	b = newBoard(t, "rnb1kbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR b KQkq - 0 1")
+	assert.Less(t, engine.Evaluate(b), -800)
+}
+
+func TestNewLevel(t *testing.T) {
+	t.Parallel()
+
+	_, err := engine.New(0)
+	assert.ErrorContains(t, err, "invalid level 0: must be between 1 and 5")
+
+	_, err = engine.New(6)
+	assert.ErrorContains(t, err, "invalid level 6")
+
+	e, err := engine.New(2)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 2, e.Limits.Depth)
+	}
+}
+
+func TestSearchMateInOne(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(3)
+
+	// back rank mate
+	res, err := e.Search(newBoard(t, "6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "a1a8", res.Move.UCI())
+	assert.Greater(t, res.Score, 90000)
+	assert.Equal(t, 1, res.Depth)
+}
+
+func TestSearchMateInTwo(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(3)
+
+	// mate with two rooks: the rook on b7 cuts off the king and the other rook mates
+	res, err := e.Search(newBoard(t, "k7/1R6/8/8/8/8/8/1R4K1 w - - 0 1"))
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Greater(t, res.Score, 90000)
+
+	// black is mated after the principal variation
+	b := newBoard(t, "k7/1R6/8/8/8/8/8/1R4K1 w - - 0 1")
+	for _, m := range res.PV {
+		if _, err = b.Move(m.UCI()); !assert.NoError(t, err) {
+			return
+		}
+	}
+	assert.Equal(t, chess.Checkmate, b.Status())
+}
+
+func TestSearchCapturesHangingQueen(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(2)
+
+	b := newBoard(t, "rnb1kbnr/pppp1ppp/8/4p1q1/4P3/3P4/PPP2PPP/RNBQKBNR w KQkq - 0 1")
+	m, err := e.BestMove(b)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Equal(t, "c1g5", m.UCI())
+}
+
+func TestSearchAvoidsLosingQueen(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(2)
+
+	// Qxd5 wins a pawn but the queen is captured by the pawn on e6
+	b := newBoard(t, "rnbqkbnr/ppp2ppp/4p3/3p4/8/8/PPP1PPPP/RNBQKBNR w KQkq - 0 1")
+	res, err := e.Search(b)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.NotEqual(t, "d1d5", res.Move.UCI())
+}
+
+func TestSearchDraws(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(2)
+
+	// black is lost but Kh8 repeats the start position
+	b := newBoard(t, "7k/8/8/8/8/8/8/K5Q1 w - - 0 1")
+	for _, m := range []string{"Qg2", "Kh7", "Qg1"} {
+		if _, err := b.Move(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	res, err := e.Search(b)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "h7h8", res.Move.UCI())
+		assert.Equal(t, 0, res.Score)
+	}
+
+	// all moves but pawn moves draw by the fifty-move rule
+	res, err = e.Search(newBoard(t, "4k3/8/8/8/8/8/P7/Q3K3 w - - 99 1"))
+	if assert.NoError(t, err) {
+		assert.Equal(t, chess.Pawn, res.Move.Piece)
+		assert.Greater(t, res.Score, 0)
+	}
+}
+
+func TestBestMoveBook(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(1)
+
+	b := chess.NewBoard()
+	for len(b.BookMoves()) > 0 {
+		m, err := e.BestMove(b)
+		if !assert.NoError(t, err) {
+			return
+		}
+		var book []chess.Move
+		for _, bm := range b.BookMoves() {
+			book = append(book, bm.Move)
+		}
+		assert.Contains(t, book, m)
+
+		if _, err = b.Move(m.UCI()); !assert.NoError(t, err) {
+			return
+		}
+
+		// book moves lead to named openings
+		_, ok := b.Opening()
+		assert.True(t, ok)
+	}
+}
+
+func TestSearchTimeLimit(t *testing.T) {
+	t.Parallel()
+
+	e := &engine.Engine{Limits: engine.Limits{Depth: 20, MoveTime: 200 * time.Millisecond}}
+
+	start := time.Now()
+	res, err := e.Search(chess.NewBoard())
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	assert.Less(t, time.Since(start), 2*time.Second)
+	assert.Less(t, res.Depth, 20)
+	assert.GreaterOrEqual(t, res.Depth, 1)
+	assert.NotEmpty(t, res.Move.UCI())
+}
+
+func TestSearchNoMoves(t *testing.T) {
+	t.Parallel()
+
+	e, _ := engine.New(1)
+
+	// stalemate
+	_, err := e.Search(newBoard(t, "k7/8/1Q6/8/8/8/8/6K1 b - - 0 1"))
+	assert.ErrorContains(t, err, "no legal moves")
+}
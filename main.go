@@ -5,20 +5,60 @@ import (
 	"fmt"
 	"log"
 	"math/rand"
+	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
 	"github.com/ekzyis/chessbot/chess"
 	"github.com/ekzyis/chessbot/db"
+	"github.com/ekzyis/chessbot/engine"
 	"github.com/ekzyis/chessbot/sn"
 )
 
 var (
-	c  = sn.GetClient()
+	c  *sn.Client
 	me *sn.User
+	// ErrNotACommand is returned for mentions and replies that are not meant for the bot
+	ErrNotACommand = errors.New("not a command")
+	// ErrStandardOnly is returned for engine commands in games of other variants
+	ErrStandardOnly = errors.New("the engine only plays standard chess")
+	// ErrChess960Variant is returned for Chess960 games of other variants since PGN can only name one variant
+	ErrChess960Variant = errors.New("Chess960 can only be played with standard rules")
+	// ErrNotYourMove is returned if someone else than the player who made the last move wants to take it back
+	ErrNotYourMove = errors.New("only the player who made the last move can take it back")
+	// ErrEngineTurn is returned for moves in games against the engine while it's the turn of the engine
+	ErrEngineTurn = errors.New("it's the turn of the engine")
+	// ErrOneMove is returned for replies with more than one move in games against the engine
+	ErrOneMove = errors.New("only one move per reply is allowed against the engine")
+	// analysis searches every position so it gets less time per position than the engine as an opponent
+	// and a total time such that long games don't hold up the replies to other notifications
+	analysisLimits = engine.Limits{Depth: 3, MoveTime: 500 * time.Millisecond, TotalTime: 5 * time.Second}
+	// hints should be good moves so the engine searches a bit deeper than usual
+	hintLimits = engine.Limits{Depth: 4, MoveTime: time.Second}
+	// replies in games against the engine mention the move of the engine like this
+	engineMoveRegexp = regexp.MustCompile(`(?m)^_I played (\S+)\._$`)
 )
 
+// game is a chess game reconstructed from a thread
+type game struct {
+	board *chess.Board
+	// first users who played white and black
+	white sn.User
+	black sn.User
+	// nil if the game is not played against the engine
+	engine *engineGame
+}
+
+// engineGame is a game against the built-in engine
+type engineGame struct {
+	engine *engine.Engine
+	// color the engine plays
+	color chess.Color
+}
+
 func main() {
+	c = sn.GetClient()
 
 	for {
 		updateMe()
@@ -146,11 +186,14 @@ func tickGameProgress(c *sn.Client) {
 
 func handleGameStart(req *sn.Item) error {
 	var (
-		move   string
-		b      *chess.Board
-		imgUrl string
-		res    string
-		err    error
+		move       string
+		variant    chess.Variant
+		b          *chess.Board
+		g          *engineGame
+		engineInfo string
+		imgUrl     string
+		res        string
+		err        error
 	)
 
 	// Immediately save game start request to db so we can store our reply to it in case of error.
@@ -165,13 +208,30 @@ func handleGameStart(req *sn.Item) error {
 		return err
 	}
 
-	// create board with initial move(s)
-	if b, err = chess.NewGame(move); err != nil {
+	if variant, move, err = parseVariant(move); err != nil {
+		return err
+	}
+
+	// remember the variant so we can replay the game with the same rules
+	if err = db.InsertGame(req.Id, variant.Name()); err != nil {
+		return fmt.Errorf("failed to insert game %d into db: %v\n", req.Id, err)
+	}
+
+	// create board with initial move(s) or position
+	if b, err = newGame(move, req.Id, variant); err != nil {
 		if rand.Float32() > 0.99 {
 			// easter egg error message
 			return errors.New("Nice try, fed.")
 		}
-		return fmt.Errorf("failed to create new game from item %d: %v\n", req.Id, err)
+		return fmt.Errorf("failed to create new game from item %d: %w\n", req.Id, err)
+	}
+
+	// engine makes the first move if it plays white
+	if g, err = parseEngineGame(move); err != nil {
+		return err
+	}
+	if engineInfo, err = playEngineMove(g, b); err != nil {
+		return fmt.Errorf("failed to play engine move in item %d: %v\n", req.Id, err)
 	}
 
 	// upload image of board
@@ -184,9 +244,22 @@ func handleGameStart(req *sn.Item) error {
 	if len(b.Moves) > 0 {
 		infoMove = "e5"
 	}
-	info := fmt.Sprintf("_A new chess game has been started!_\n\n"+
+	kind := "chess game"
+	if n, ok, _ := parseChess960(move, req.Id); ok {
+		kind = fmt.Sprintf("Chess960 game (position %d)", n)
+	}
+	if variant != chess.Standard {
+		kind = fmt.Sprintf("game of %s", variant.Name())
+	}
+	info := fmt.Sprintf("_A new %s has been started!_\n\n"+
 		"_Reply with a move like `%s` to continue the game. "+
-		"See [here](https://stacker.news/chess#how-to-continue) for details._", infoMove)
+		"See [here](https://stacker.news/chess#how-to-continue) for details._", kind, infoMove)
+	if engineInfo != "" {
+		info = fmt.Sprintf("%s\n\n%s", engineInfo, info)
+	}
+	if opening := openingInfo(b); opening != "" {
+		info = fmt.Sprintf("%s\n\n%s", opening, info)
+	}
 	res = strings.Trim(fmt.Sprintf("%s\n\n%s\n\n%s", b.AlgebraicNotation(), imgUrl, info), " ")
 	if _, err = createComment(req.Id, res); err != nil {
 		return fmt.Errorf("failed to reply to item %d: %v\n", req.Id, err)
@@ -197,12 +270,15 @@ func handleGameStart(req *sn.Item) error {
 
 func handleGameProgress(req *sn.Item) error {
 	var (
-		thread []sn.Item
-		b             = chess.NewBoard()
-		move   string = strings.Trim(req.Text, " ")
-		imgUrl string
-		res    string
-		err    error
+		thread     []sn.Item
+		variant    chess.Variant
+		g          *game
+		b          *chess.Board
+		move       string = strings.Trim(req.Text, " ")
+		engineInfo string
+		imgUrl     string
+		res        string
+		err        error
 	)
 
 	// immediately save game update request to db so we can store our reply to it in case of error
@@ -215,21 +291,14 @@ func handleGameProgress(req *sn.Item) error {
 		return fmt.Errorf("failed to fetch thread for item %d: %v\n", req.ParentId, err)
 	}
 
-	for _, item := range thread {
-		if item.User.Id == me.Id {
-			continue
-		}
-
-		var moves string
-		if moves, err = parseGameProgress(item.Text); err != nil {
-			return err
-		}
+	if variant, err = gameVariant(thread[0].Id); err != nil {
+		return err
+	}
 
-		// parse and execute existing moves
-		if err = b.Parse(moves); err != nil {
-			return err
-		}
+	if g, err = replayGame(thread, variant); err != nil {
+		return err
 	}
+	b = g.board
 
 	// parse and execute new move
 
@@ -237,7 +306,23 @@ func handleGameProgress(req *sn.Item) error {
 		return err
 	}
 
-	if err = b.Parse(move); err != nil {
+	if strings.ToLower(move) == "pgn" {
+		return handlePGN(req, thread, g)
+	}
+
+	if strings.ToLower(move) == "analyze" {
+		return handleAnalyze(req, thread, g)
+	}
+
+	if hint := strings.ToLower(move); hint == "hint" || hint == "hint soft" {
+		return handleHint(req, g, hint == "hint soft")
+	}
+
+	if isTakeback(move) {
+		if err = takeback(g, req.User); err != nil {
+			return err
+		}
+	} else if err = playMoves(g, move); err != nil {
 		if rand.Float32() > 0.99 {
 			// easter egg error message
 			return errors.New("Nice try, fed.")
@@ -245,13 +330,27 @@ func handleGameProgress(req *sn.Item) error {
 		return err
 	}
 
+	// answer with move of the engine in the same reply
+	if engineInfo, err = playEngineMove(g.engine, b); err != nil {
+		return fmt.Errorf("failed to play engine move in item %d: %v\n", req.Id, err)
+	}
+
 	// upload image of updated board
 	if imgUrl, err = c.UploadImage(b.Image()); err != nil {
 		return fmt.Errorf("failed to upload image for item %d: %v\n", req.Id, err)
 	}
 
-	// reply with algebraic notation and image
+	// reply with algebraic notation, image and result if game is over
 	res = strings.Trim(fmt.Sprintf("%s\n\n%s", b.AlgebraicNotation(), imgUrl), " ")
+	if engineInfo != "" {
+		res = fmt.Sprintf("%s\n\n%s", res, engineInfo)
+	}
+	if opening := openingInfo(b); opening != "" {
+		res = fmt.Sprintf("%s\n\n%s", res, opening)
+	}
+	if info := gameOverInfo(b); info != "" {
+		res = fmt.Sprintf("%s\n\n%s", res, info)
+	}
 	if _, err = createComment(req.Id, res); err != nil {
 		return fmt.Errorf("failed to reply to item %d: %v\n", req.Id, err)
 	}
@@ -259,25 +358,492 @@ func handleGameProgress(req *sn.Item) error {
 	return nil
 }
 
-func handleError(req *sn.Item, err error) {
+func handlePGN(req *sn.Item, thread []sn.Item, g *game) error {
+	var (
+		res = fmt.Sprintf("```\n%s```", g.board.PGN(pgnTags(thread, g)))
+		err error
+	)
 
-	// don't reply to mentions that we failed to parse as a game start
-	// to support unrelated mentions
-	if err.Error() == "failed to parse game start" {
-		log.Printf("ignoring error for item %d: %v\n", req.Id, err)
-		return
+	if _, err = createComment(req.Id, res); err != nil {
+		return fmt.Errorf("failed to reply to item %d: %v\n", req.Id, err)
+	}
+
+	return nil
+}
+
+func handleAnalyze(req *sn.Item, thread []sn.Item, g *game) error {
+	var (
+		b        = g.board
+		e        = &engine.Engine{Limits: analysisLimits}
+		tags     = pgnTags(thread, g)
+		analysis []engine.MoveAnalysis
+		imgUrl   string
+		res      string
+		err      error
+	)
+
+	if b.Status() == chess.Ongoing {
+		return errors.New("game is not over yet")
+	}
+
+	if b.Variant() != chess.Standard {
+		return ErrStandardOnly
+	}
+
+	if analysis, err = e.Analyze(b); err != nil {
+		return fmt.Errorf("failed to analyze game in item %d: %v\n", req.Id, err)
+	}
+
+	// the critical move is the one that lost the most compared to the best move
+	annotations := make([]chess.Annotation, len(analysis))
+	critical := -1
+	for i, a := range analysis {
+		annotations[i] = a.Annotation()
+		if a.Judgment != engine.Good && (critical == -1 || a.Loss > analysis[critical].Loss) {
+			critical = i
+		}
+	}
+
+	tags["Annotator"] = me.Name
+	res = fmt.Sprintf("```\n%s```", b.AnnotatedPGN(tags, annotations))
+
+	if critical == -1 {
+		res = fmt.Sprintf("%s\n\n_No inaccuracies, mistakes or blunders found. Well played!_", res)
+	} else {
+		// show the position before the critical move
+		pos := b.Clone()
+		for len(pos.Moves) > critical {
+			if err = pos.Undo(); err != nil {
+				return err
+			}
+		}
+
+		if imgUrl, err = c.UploadImage(pos.Image()); err != nil {
+			return fmt.Errorf("failed to upload image for item %d: %v\n", req.Id, err)
+		}
+
+		a := analysis[critical]
+		color := "White"
+		if pos.Turn() == chess.Dark {
+			color = "Black"
+		}
+		res = fmt.Sprintf("%s\n\n_The critical moment was %s's move %s%s. %s was best._\n\n%s",
+			res, color, a.Move.SAN, string(a.Judgment), a.Best.SAN, imgUrl)
+	}
+
+	if _, err = createComment(req.Id, res); err != nil {
+		return fmt.Errorf("failed to reply to item %d: %v\n", req.Id, err)
+	}
+
+	return nil
+}
+
+// handleHint replies with the best move the engine found without playing it.
+// Hints always search since book moves are only picked at random.
+// Soft hints only mention which piece to move.
+func handleHint(req *sn.Item, g *game, soft bool) error {
+	var (
+		b      = g.board
+		e      = &engine.Engine{Limits: hintLimits}
+		m      chess.Move
+		result engine.Result
+		imgUrl string
+		res    string
+		err    error
+	)
+
+	if b.Status() != chess.Ongoing {
+		return chess.ErrGameOver
+	}
+
+	if b.Variant() != chess.Standard {
+		return ErrStandardOnly
+	}
+
+	if result, err = e.Search(b); err != nil {
+		return fmt.Errorf("failed to find hint for item %d: %v\n", req.Id, err)
+	}
+	m = result.Move
+
+	if soft {
+		res = fmt.Sprintf("_Look at your %s._", m.Piece.FullName())
+	} else {
+		if imgUrl, err = c.UploadImage(b.ImageWithArrow(m)); err != nil {
+			return fmt.Errorf("failed to upload image for item %d: %v\n", req.Id, err)
+		}
+		res = fmt.Sprintf("_Try %s._\n\n%s", b.SAN(m), imgUrl)
+	}
+
+	if _, err = createComment(req.Id, res); err != nil {
+		return fmt.Errorf("failed to reply to item %d: %v\n", req.Id, err)
+	}
+
+	return nil
+}
+
+// pgnTags returns the PGN tags for the game in the thread
+func pgnTags(thread []sn.Item, g *game) map[string]string {
+	return map[string]string{
+		"Event": "Stacker News chess game",
+		"Site":  fmt.Sprintf("%s/items/%d", c.BaseUrl, thread[0].Id),
+		"Date":  thread[0].CreatedAt.Format("2006.01.02"),
+		"Round": "-",
+		"White": g.white.Name,
+		"Black": g.black.Name,
+	}
+}
+
+// replayGame reconstructs the board from all moves in the thread.
+// It also remembers the names of the first users who played white and black.
+func replayGame(thread []sn.Item, variant chess.Variant) (*game, error) {
+	var (
+		g   = &game{}
+		err error
+	)
+
+	// remember who made the given number of moves starting with the given color
+	addPlayer := func(user sn.User, turn chess.Color, moves int) {
+		for i := 0; i < moves && i < 2; i++ {
+			if turn == chess.Light && g.white.Id == 0 {
+				g.white = user
+			} else if turn == chess.Dark && g.black.Id == 0 {
+				g.black = user
+			}
+
+			if turn == chess.Light {
+				turn = chess.Dark
+			} else {
+				turn = chess.Light
+			}
+		}
+	}
+
+	for i, item := range thread {
+		if item.User.Id == me.Id {
+			// in games against the engine, our replies contain the moves of the engine
+			if g.engine == nil || g.board == nil {
+				continue
+			}
+
+			match := engineMoveRegexp.FindStringSubmatch(item.Text)
+			if match == nil {
+				continue
+			}
+
+			turn := g.board.Turn()
+			if _, err = g.board.Move(match[1]); err != nil {
+				return nil, err
+			}
+			addPlayer(*me, turn, 1)
+			continue
+		}
+
+		if i == 0 {
+			// first item in thread started the game
+			var start string
+			if start, err = parseGameStart(item.Text); err != nil {
+				return nil, err
+			}
+
+			// the given variant was taken from the db
+			if _, start, err = parseVariant(start); err != nil {
+				return nil, err
+			}
+
+			if g.board, err = newGame(start, item.Id, variant); err != nil {
+				return nil, err
+			}
+
+			if g.engine, err = parseEngineGame(start); err != nil {
+				return nil, err
+			}
+
+			// initial moves always start with white
+			addPlayer(item.User, chess.Light, len(g.board.Moves))
+			continue
+		}
+
+		var moves string
+		if moves, err = parseGameProgress(item.Text); err != nil {
+			return nil, err
+		}
+
+		if isTakeback(moves) {
+			if err = takeback(g, item.User); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		if isCommand(moves) {
+			continue
+		}
+
+		// parse and execute existing moves
+		turn, n := g.board.Turn(), len(g.board.Moves)
+		if err = playMoves(g, moves); err != nil {
+			return nil, err
+		}
+		addPlayer(item.User, turn, len(g.board.Moves)-n)
+	}
+
+	return g, nil
+}
+
+// playMoves executes the moves of a reply.
+// In games against the engine, players can only make one move and only when it's their turn.
+func playMoves(g *game, moves string) error {
+	var (
+		b    = g.board
+		game *chess.PGNGame
+		err  error
+	)
+
+	if g.engine != nil && b.Status() == chess.Ongoing {
+		if b.Turn() == g.engine.color {
+			return ErrEngineTurn
+		}
+
+		if game, err = chess.ParsePGN(moves); err != nil {
+			return err
+		}
+
+		if len(game.MainLine()) > 1 {
+			return ErrOneMove
+		}
+	}
+
+	return b.Parse(moves)
+}
+
+// takeback undoes the last move.
+// In games against the engine, the move of the engine is undone as well such that it's the turn of the player again.
+// Only the player who made the last move can take it back and only while the game is ongoing.
+func takeback(g *game, user sn.User) error {
+	var (
+		b = g.board
+		n = 1
+		// color of the player who made the last move
+		color = otherColor(b.Turn())
+	)
+
+	if g.engine != nil {
+		// players can only take back their own moves
+		color = otherColor(g.engine.color)
+		if b.Turn() != g.engine.color {
+			n = 2
+		}
+	}
+
+	if b.Status() != chess.Ongoing {
+		return chess.ErrGameOver
+	}
+
+	if len(b.Moves) < n {
+		return chess.ErrNoMoveToUndo
+	}
+
+	if player := g.player(color); player.Id != user.Id {
+		return ErrNotYourMove
+	}
+
+	for ; n > 0; n-- {
+		if err := b.Undo(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// player returns the first user who played the given color.
+func (g *game) player(color chess.Color) sn.User {
+	if color == chess.Light {
+		return g.white
+	}
+	return g.black
+}
+
+func otherColor(color chess.Color) chess.Color {
+	if color == chess.Light {
+		return chess.Dark
+	}
+	return chess.Light
+}
+
+// parseEngineGame parses game starts like "play", "play black" or "play white 5" to play against the engine.
+// It returns nil if the game is not played against the engine.
+func parseEngineGame(start string) (*engineGame, error) {
+	var (
+		args  = strings.Fields(strings.ToLower(start))
+		color = chess.Dark
+		level = engine.DefaultLevel
+		err   error
+	)
+
+	if len(args) == 0 || args[0] != "play" {
+		return nil, nil
+	}
+	args = args[1:]
+
+	if len(args) > 0 {
+		switch args[0] {
+		case "me", "white":
+			args = args[1:]
+		case "black":
+			color = chess.Light
+			args = args[1:]
+		}
+	}
+
+	if len(args) > 0 {
+		if level, err = strconv.Atoi(args[0]); err != nil {
+			return nil, fmt.Errorf("invalid level: %s", args[0])
+		}
+		args = args[1:]
+	}
+
+	if len(args) > 0 {
+		return nil, fmt.Errorf("invalid game start: %s", start)
 	}
 
-	if err.Error() == "failed to parse game update" {
+	e, err := engine.New(level)
+	if err != nil {
+		return nil, err
+	}
+
+	return &engineGame{engine: e, color: color}, nil
+}
+
+// playEngineMove lets the engine make its move if it's its turn.
+// It returns the info about the move for the reply.
+func playEngineMove(g *engineGame, b *chess.Board) (string, error) {
+	if g == nil || b.Turn() != g.color || b.Status() != chess.Ongoing {
+		return "", nil
+	}
+
+	m, err := g.engine.BestMove(b)
+	if err != nil {
+		return "", err
+	}
+
+	if m, err = b.Move(m.UCI()); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("_I played %s._", m.SAN), nil
+}
+
+// isCommand returns true if the text of a reply in a game thread was a command and not a move
+func isCommand(text string) bool {
+	switch strings.ToLower(text) {
+	case "pgn", "analyze", "hint", "hint soft":
+		return true
+	default:
+		return false
+	}
+}
+
+// isTakeback returns true if the text of a reply in a game thread asked to take back the last move
+func isTakeback(text string) bool {
+	switch strings.ToLower(text) {
+	case "undo", "takeback":
+		return true
+	default:
+		return false
+	}
+}
+
+func handleError(req *sn.Item, err error) {
+	var (
+		illegal   *chess.IllegalMoveError
+		ambiguous *chess.AmbiguousMoveError
+		gameOver  *chess.GameOverError
+		parse     *chess.ParseError
+		res       string
+	)
+
+	switch {
+	case errors.Is(err, ErrNotACommand):
+		// don't reply to mentions and replies that we failed to parse
+		// to support unrelated mentions
 		log.Printf("ignoring error for item %d: %v\n", req.Id, err)
 		return
+	case errors.As(err, &ambiguous):
+		res = fmt.Sprintf("_Move `%s` is ambiguous. Did you mean %s?_", ambiguous.Move, formatMoves(ambiguous.Candidates))
+	case errors.As(err, &illegal):
+		res = fmt.Sprintf("_Illegal move `%s`: %s._", illegal.Move, illegal.Reason)
+		if len(illegal.Suggestions) > 0 {
+			res = fmt.Sprintf("%s\n\n_Did you mean %s?_", res, formatMoves(illegal.Suggestions))
+		}
+	case errors.As(err, &gameOver):
+		res = fmt.Sprintf("_The game already ended by %s._", gameOver.Status)
+	case errors.Is(err, chess.ErrGameOver):
+		res = "_The game is already over._"
+	case errors.Is(err, chess.ErrNoMoveToUndo):
+		res = "_There is no move to take back._"
+	case errors.Is(err, ErrNotYourMove):
+		res = "_Only the player who made the last move can take it back._"
+	case errors.Is(err, ErrEngineTurn):
+		res = "_It's my turn._"
+	case errors.Is(err, ErrOneMove):
+		res = "_Only one move per reply, please._"
+	case errors.As(err, &parse):
+		res = fmt.Sprintf("_Invalid %s: %s._", parse.Notation, parse.Reason)
+		if len(parse.Suggestions) > 0 {
+			res = fmt.Sprintf("%s\n\n_Did you mean %s?_", res, formatMoves(parse.Suggestions))
+		}
+	default:
+		res = fmt.Sprintf("`%v`", err)
 	}
 
-	if _, err2 := createComment(req.Id, fmt.Sprintf("`%v`", err)); err2 != nil {
+	if _, err2 := createComment(req.Id, res); err2 != nil {
 		log.Printf("failed to reply with error to item %d: %v\n", req.Id, err2)
 	} else {
-		log.Printf("replied to game start in item %d with error: %v\n", req.Id, err)
+		log.Printf("replied to item %d with error: %v\n", req.Id, err)
+	}
+}
+
+// formatMoves lists the moves in SAN like "`Nf3`, `Nc3` or `Nh3`"
+func formatMoves(moves []chess.Move) string {
+	sans := make([]string, len(moves))
+	for i, m := range moves {
+		sans[i] = fmt.Sprintf("`%s`", m.SAN)
+	}
+
+	if len(sans) == 1 {
+		return sans[0]
+	}
+	return fmt.Sprintf("%s or %s", strings.Join(sans[:len(sans)-1], ", "), sans[len(sans)-1])
+}
+
+func gameOverInfo(b *chess.Board) string {
+	status := b.Status()
+
+	winner := "White"
+	if b.Result() == chess.BlackWins {
+		winner = "Black"
+	}
+
+	switch {
+	case status == chess.Ongoing:
+		return ""
+	case status == chess.Checkmate:
+		return fmt.Sprintf("_Checkmate! %s wins (%s)._", winner, b.Result())
+	case b.Result() != chess.Draw:
+		// variants are also won by other means
+		return fmt.Sprintf("_%s wins by %s (%s)._", winner, status, b.Result())
+	default:
+		return fmt.Sprintf("_The game ended in a draw by %s (%s)._", status, b.Result())
+	}
+}
+
+// openingInfo mentions the opening while the game is still in the opening book
+func openingInfo(b *chess.Board) string {
+	if o, ok := b.Opening(); ok {
+		return fmt.Sprintf("_Opening: %s_", o)
 	}
+	return ""
 }
 
 func createComment(parentId int, text string) (*sn.Item, error) {
@@ -302,8 +868,131 @@ func createComment(parentId int, text string) (*sn.Item, error) {
 	return comment, nil
 }
 
+// newGame creates a board with the rules of the given variant
+// from a game start like "e4", "fen <FEN>", "pgn <PGN>", "960" or "play black".
+// The id of the item that started the game picks the Chess960 position if none was given.
+func newGame(start string, id int, variant chess.Variant) (*chess.Board, error) {
+	var (
+		b   *chess.Board
+		err error
+	)
+
+	if g, err := parseEngineGame(start); err != nil {
+		return nil, err
+	} else if g != nil {
+		if variant != chess.Standard {
+			return nil, ErrStandardOnly
+		}
+		return chess.NewBoard(), nil
+	}
+
+	if pgn, found := strings.CutPrefix(start, "pgn"); found {
+		if variant != chess.Standard {
+			return nil, errors.New("use the Variant tag to import games of other variants")
+		}
+		// PGN might have been pasted as a code block
+		return chess.NewGameFromPGN(strings.ReplaceAll(pgn, "```", ""))
+	}
+
+	if n, ok, err := parseChess960(start, id); err != nil {
+		return nil, err
+	} else if ok {
+		if variant != chess.Standard {
+			return nil, ErrChess960Variant
+		}
+		if b, err = chess.NewChess960Board(n); err != nil {
+			return nil, err
+		}
+		b.SetVariant(variant)
+		return b, nil
+	}
+
+	if fen, found := strings.CutPrefix(start, "fen "); found {
+		if b, err = chess.NewBoardFromFEN(fen); err != nil {
+			return nil, err
+		}
+		if b.Chess960() && variant != chess.Standard {
+			return nil, ErrChess960Variant
+		}
+		b.SetVariant(variant)
+		return b, nil
+	}
+
+	// initial moves must already follow the rules of the variant
+	b = chess.NewBoard()
+	b.SetVariant(variant)
+	if err = b.Parse(start); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// parseVariant parses the variant option of game starts like "variant=threecheck e4".
+// It returns the game start without the option.
+func parseVariant(start string) (chess.Variant, string, error) {
+	option, rest, _ := strings.Cut(start, " ")
+
+	name, found := strings.CutPrefix(strings.ToLower(option), "variant=")
+	if !found {
+		return chess.Standard, start, nil
+	}
+
+	variant, ok := chess.ParseVariant(name)
+	if !ok {
+		return nil, "", fmt.Errorf("unknown variant: %s", name)
+	}
+
+	return variant, strings.Trim(rest, " "), nil
+}
+
+// gameVariant returns the variant of the game started by the item with the given id.
+func gameVariant(id int) (chess.Variant, error) {
+	name, err := db.GetVariant(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch variant of game %d: %v\n", id, err)
+	}
+
+	if name == "" {
+		// games started before variants were recorded
+		return chess.Standard, nil
+	}
+
+	variant, ok := chess.ParseVariant(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown variant: %s", name)
+	}
+
+	return variant, nil
+}
+
+// parseChess960 parses game starts like "960" or "960 123" to play Chess960.
+// Without a position number, the position is derived from the given id
+// such that replaying the game leads to the same position.
+func parseChess960(start string, id int) (int, bool, error) {
+	args := strings.Fields(start)
+
+	if len(args) == 0 || args[0] != "960" {
+		return 0, false, nil
+	}
+
+	switch len(args) {
+	case 1:
+		return id % chess.Chess960Positions, true, nil
+	case 2:
+		n, err := strconv.Atoi(args[1])
+		if err != nil {
+			return 0, true, fmt.Errorf("invalid Chess960 position: %s", args[1])
+		}
+		return n, true, nil
+	default:
+		return 0, true, fmt.Errorf("invalid game start: %s", start)
+	}
+}
+
 func parseGameStart(input string) (string, error) {
-	for _, line := range strings.Split(input, "\n") {
+	lines := strings.Split(input, "\n")
+
+	for i, line := range lines {
 		line = strings.Trim(line, " ")
 
 		var found bool
@@ -311,10 +1000,17 @@ func parseGameStart(input string) (string, error) {
 			continue
 		}
 
-		return strings.Trim(line, " "), nil
+		line = strings.Trim(line, " ")
+
+		// imported PGN spans all following lines
+		if line == "pgn" || strings.HasPrefix(line, "pgn ") {
+			return strings.Join(append([]string{line}, lines[i+1:]...), "\n"), nil
+		}
+
+		return line, nil
 	}
 
-	return "", errors.New("failed to parse game start")
+	return "", fmt.Errorf("failed to parse game start: %w", ErrNotACommand)
 }
 
 func parseGameProgress(input string) (string, error) {
@@ -327,6 +1023,11 @@ func parseGameProgress(input string) (string, error) {
 		return strings.Trim(strings.ReplaceAll(input, "@chess", ""), " "), nil
 	}
 
+	// commands with arguments like "hint soft" also work without mention
+	if len(lines) == 1 && isCommand(input) {
+		return input, nil
+	}
+
 	for _, line := range strings.Split(input, "\n") {
 		line = strings.Trim(line, " ")
 
@@ -338,7 +1039,7 @@ func parseGameProgress(input string) (string, error) {
 		return strings.Trim(line, " "), nil
 	}
 
-	return "", errors.New("failed to parse game update")
+	return "", fmt.Errorf("failed to parse game update: %w", ErrNotACommand)
 }
 
 func isRecent(t time.Time) bool {
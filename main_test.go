@@ -0,0 +1,159 @@
+package main
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/ekzyis/chessbot/sn"
+	"github.com/stretchr/testify/assert"
+)
+
+var (
+	alice = sn.User{Id: 2, Name: "alice"}
+	bob   = sn.User{Id: 3, Name: "bob"}
+	carol = sn.User{Id: 4, Name: "carol"}
+)
+
+func TestMain(m *testing.M) {
+	me = &sn.User{Id: 1, Name: "chess"}
+	os.Exit(m.Run())
+}
+
+func TestTakeback(t *testing.T) {
+	t.Parallel()
+
+	g := mustReplay(t, thread(alice, "@chess e4", bob, "e5"))
+
+	// only the player who made the last move can take it back
+	assert.ErrorIs(t, takeback(g, alice), ErrNotYourMove)
+	assert.ErrorIs(t, takeback(g, carol), ErrNotYourMove)
+	assert.NoError(t, takeback(g, bob))
+	assert.Equal(t, "e4", sans(g))
+
+	assert.NoError(t, takeback(g, alice))
+	assert.ErrorIs(t, takeback(g, alice), chess.ErrNoMoveToUndo)
+
+	// moves can't be taken back after the game ended
+	g = mustReplay(t, thread(alice, "@chess f3", bob, "e5", alice, "g4", bob, "Qh4#"))
+	assert.ErrorIs(t, takeback(g, bob), chess.ErrGameOver)
+}
+
+func TestTakebackEngine(t *testing.T) {
+	t.Parallel()
+
+	g := mustReplay(t, thread(alice, "@chess play", alice, "e4", *me, "_I played e5._"))
+
+	// the move of the engine is taken back as well
+	assert.ErrorIs(t, takeback(g, carol), ErrNotYourMove)
+	assert.NoError(t, takeback(g, alice))
+	assert.Empty(t, g.board.Moves)
+	assert.Equal(t, chess.Light, g.board.Turn())
+}
+
+func TestReplayTakeback(t *testing.T) {
+	t.Parallel()
+
+	g := mustReplay(t, thread(alice, "@chess e4", bob, "e5", bob, "undo", bob, "c5"))
+	assert.Equal(t, "e4 c5", sans(g))
+	assert.Equal(t, alice, g.white)
+	assert.Equal(t, bob, g.black)
+
+	g = mustReplay(t, thread(alice, "@chess play", alice, "e4", *me, "_I played e5._", alice, "undo", alice, "d4"))
+	assert.Equal(t, "d4", sans(g))
+
+	// takebacks of others break the replay like any other invalid reply
+	_, err := replayGame(thread(alice, "@chess e4", bob, "e5", carol, "undo"), chess.Standard)
+	assert.ErrorIs(t, err, ErrNotYourMove)
+}
+
+func TestPlayMovesEngine(t *testing.T) {
+	t.Parallel()
+
+	g := mustReplay(t, thread(alice, "@chess play", alice, "e4", *me, "_I played e5._"))
+
+	// only one move per reply against the engine
+	assert.ErrorIs(t, playMoves(g, "Nf3 Nc6"), ErrOneMove)
+	assert.ErrorIs(t, playMoves(g, "2. Nf3 Nc6"), ErrOneMove)
+	assert.Equal(t, "e4 e5", sans(g))
+
+	// no moves while it's the turn of the engine
+	assert.NoError(t, playMoves(g, "Nf3"))
+	assert.ErrorIs(t, playMoves(g, "Nc6"), ErrEngineTurn)
+	assert.Equal(t, "e4 e5 Nf3", sans(g))
+
+	// the replay enforces the same rules
+	_, err := replayGame(thread(alice, "@chess play", alice, "@chess e4 e5"), chess.Standard)
+	assert.ErrorIs(t, err, ErrOneMove)
+	_, err = replayGame(thread(alice, "@chess play black", alice, "e4"), chess.Standard)
+	assert.ErrorIs(t, err, ErrEngineTurn)
+
+	// games between players still accept multiple moves
+	g = mustReplay(t, thread(alice, "@chess e4", bob, "@chess e5 Nf3"))
+	assert.Equal(t, "e4 e5 Nf3", sans(g))
+}
+
+func TestParseGameProgress(t *testing.T) {
+	t.Parallel()
+
+	for input, expected := range map[string]string{
+		"e4":               "e4",
+		"@chess e4":        "e4",
+		"hint":             "hint",
+		"hint soft":        "hint soft",
+		"@chess hint soft": "hint soft",
+		"undo":             "undo",
+	} {
+		move, err := parseGameProgress(input)
+		assert.NoError(t, err, input)
+		assert.Equal(t, expected, move, input)
+	}
+
+	_, err := parseGameProgress("nice move")
+	assert.ErrorIs(t, err, ErrNotACommand)
+}
+
+func TestNewGameChess960Variant(t *testing.T) {
+	t.Parallel()
+
+	// PGN can't name both Chess960 and another variant
+	_, err := newGame("960 0", 1, chess.KingOfTheHill)
+	assert.ErrorIs(t, err, ErrChess960Variant)
+	_, err = newGame("fen rk2r3/8/8/8/8/8/8/RK2R3 w KQkq - 0 1", 1, chess.ThreeCheck)
+	assert.ErrorIs(t, err, ErrChess960Variant)
+
+	b, err := newGame("960 0", 1, chess.Standard)
+	if assert.NoError(t, err) {
+		assert.True(t, b.Chess960())
+	}
+}
+
+// thread returns items with the given users and texts in alternating order
+func thread(args ...any) []sn.Item {
+	var items []sn.Item
+	for i := 0; i < len(args); i += 2 {
+		items = append(items, sn.Item{Id: i/2 + 1, User: args[i].(sn.User), Text: args[i+1].(string)})
+	}
+	return items
+}
+
+// sans returns the moves of the game in SAN
+func sans(g *game) string {
+	var moves []string
+	for _, m := range g.board.Moves {
+		moves = append(moves, m.SAN)
+	}
+	return strings.Join(moves, " ")
+}
+
+func mustReplay(t *testing.T, thread []sn.Item) *game {
+	g, err := replayGame(thread, chess.Standard)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(thread[0].Text, "play") {
+		assert.NotNil(t, g.engine)
+	}
+	return g
+}
@@ -0,0 +1,54 @@
+#!/bin/sh
+# fake UCI engine for tests
+# It plays 1.e4 e5 in the start position and mates with Ra8# in a back rank mate position.
+
+position=""
+
+while read -r cmd args; do
+	case "$cmd" in
+	uci)
+		echo "id name Fake Engine 1.0"
+		echo "id author chessbot"
+		echo "option name Skill Level type spin default 20 min 0 max 20"
+		echo "uciok"
+		;;
+	isready)
+		echo "readyok"
+		;;
+	position)
+		position="$args"
+		;;
+	go)
+		case "$position" in
+		"fen rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1")
+			echo "info depth 1 score cp 20 nodes 21 pv e2e4"
+			echo "info string thinking hard"
+			echo "info depth 2 seldepth 3 multipv 1 score cp 31 nodes 142 nps 142000 time 1 pv e2e4 e7e5"
+			echo "bestmove e2e4 ponder e7e5"
+			;;
+		"fen rnbqkbnr/pppppppp/8/8/8/8/PPPPPPPP/RNBQKBNR w KQkq - 0 1 moves e2e4")
+			echo "info depth 2 score cp -25 nodes 120 pv e7e5 g1f3 b8c6 xxxx"
+			echo "bestmove e7e5"
+			;;
+		"fen 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+			echo "info depth 1 score mate 1 nodes 8 pv a1a8"
+			echo "bestmove a1a8"
+			;;
+		"fen 6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1 moves a1a2")
+			echo "bestmove e2e4"
+			;;
+		"fen 4k3/8/8/8/8/8/4P3/4K3 w - - 0 1")
+			# never answers until told to stop
+			read -r cmd
+			echo "bestmove e1d1"
+			;;
+		*)
+			echo "bestmove (none)"
+			;;
+		esac
+		;;
+	quit)
+		exit 0
+		;;
+	esac
+done
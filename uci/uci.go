@@ -0,0 +1,338 @@
+// Package uci talks to chess engines like Stockfish that run as a subprocess
+// and speak the Universal Chess Interface protocol.
+// See https://backscattering.de/chess/uci/ for the protocol.
+package uci
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+)
+
+// DefaultTimeout is how long to wait for a response of the engine
+// on top of the time it was given to search.
+const DefaultTimeout = 5 * time.Second
+
+// Result is the outcome of a search.
+type Result struct {
+	Move chess.Move
+	// score in centipawns from the point of view of the player to move; zero if a mate was found
+	Score int
+	// number of moves until mate; negative if the player to move gets mated and zero if no mate was found
+	Mate  int
+	Depth int
+	Nodes int
+	// principal variation: the best line of play found, starting with Move
+	PV []chess.Move
+}
+
+type Engine struct {
+	// name and author as reported by the engine
+	Name   string
+	Author string
+
+	Timeout time.Duration
+
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	lines chan string
+	done  chan struct{}
+}
+
+// Start runs the engine at the given path and waits until it is ready.
+func Start(path string, args ...string) (*Engine, error) {
+	var (
+		cmd    = exec.Command(path, args...)
+		stdin  io.WriteCloser
+		stdout io.ReadCloser
+		err    error
+	)
+
+	if stdin, err = cmd.StdinPipe(); err != nil {
+		return nil, err
+	}
+
+	if stdout, err = cmd.StdoutPipe(); err != nil {
+		return nil, err
+	}
+
+	if err = cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start engine %s: %v", path, err)
+	}
+
+	e := &Engine{
+		Timeout: DefaultTimeout,
+		cmd:     cmd,
+		stdin:   stdin,
+		lines:   make(chan string),
+		done:    make(chan struct{}),
+	}
+	go e.read(stdout)
+
+	if err = e.handshake(); err != nil {
+		e.Close()
+		return nil, err
+	}
+
+	return e, nil
+}
+
+// SetOption sets an option of the engine like "Skill Level" or "Threads".
+func (e *Engine) SetOption(name string, value string) error {
+	if err := e.send("setoption name %s value %s", name, value); err != nil {
+		return err
+	}
+	return e.isReady()
+}
+
+// NewGame tells the engine that the next search is from a different game.
+func (e *Engine) NewGame() error {
+	if err := e.send("ucinewgame"); err != nil {
+		return err
+	}
+	return e.isReady()
+}
+
+// Search lets the engine search the position on the board for the given time
+// and returns the best move it found.
+func (e *Engine) Search(b *chess.Board, moveTime time.Duration) (Result, error) {
+	var (
+		res    Result
+		pv     []string
+		best   string
+		fields []string
+		err    error
+	)
+
+	if err = e.position(b); err != nil {
+		return Result{}, err
+	}
+
+	if err = e.send("go movetime %d", moveTime.Milliseconds()); err != nil {
+		return Result{}, err
+	}
+
+	handle := func(line string) bool {
+		fields = strings.Fields(line)
+		switch fields[0] {
+		case "info":
+			parseInfo(fields[1:], &res, &pv)
+		case "bestmove":
+			if len(fields) > 1 {
+				best = fields[1]
+			}
+			return true
+		}
+		return false
+	}
+
+	if err = e.readUntil(moveTime+e.Timeout, handle); err != nil {
+		// tell the engine to stop searching and give it another chance to respond
+		if err = e.send("stop"); err != nil {
+			return Result{}, err
+		}
+		if err = e.readUntil(e.Timeout, handle); err != nil {
+			return Result{}, err
+		}
+	}
+
+	if best == "" || best == "(none)" || best == "0000" {
+		return Result{}, errors.New("engine found no move")
+	}
+
+	if res.Move, err = b.Clone().Move(best); err != nil {
+		return Result{}, fmt.Errorf("engine played invalid move %s: %v", best, err)
+	}
+
+	res.PV = parsePV(b, pv)
+
+	return res, nil
+}
+
+// BestMove returns the best move the engine found within the given time.
+func (e *Engine) BestMove(b *chess.Board, moveTime time.Duration) (chess.Move, error) {
+	res, err := e.Search(b, moveTime)
+	if err != nil {
+		return chess.Move{}, err
+	}
+	return res.Move, nil
+}
+
+// Close tells the engine to quit and kills it if it doesn't.
+func (e *Engine) Close() error {
+	select {
+	case <-e.done:
+		return nil
+	default:
+	}
+
+	// errors are ignored since the engine might already have exited
+	e.send("quit")
+	e.stdin.Close()
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- e.cmd.Wait()
+	}()
+
+	var err error
+	select {
+	case err = <-exited:
+	case <-time.After(e.Timeout):
+		e.cmd.Process.Kill()
+		err = <-exited
+	}
+
+	close(e.done)
+
+	return err
+}
+
+func (e *Engine) handshake() error {
+	if err := e.send("uci"); err != nil {
+		return err
+	}
+
+	err := e.readUntil(e.Timeout, func(line string) bool {
+		if name, found := strings.CutPrefix(line, "id name "); found {
+			e.Name = name
+		} else if author, found := strings.CutPrefix(line, "id author "); found {
+			e.Author = author
+		}
+		return line == "uciok"
+	})
+	if err != nil {
+		return err
+	}
+
+	return e.isReady()
+}
+
+func (e *Engine) isReady() error {
+	if err := e.send("isready"); err != nil {
+		return err
+	}
+
+	return e.readUntil(e.Timeout, func(line string) bool {
+		return line == "readyok"
+	})
+}
+
+// position sends the position the game started from and all moves since then
+// such that the engine knows about repetitions.
+func (e *Engine) position(b *chess.Board) error {
+	cmd := "position fen " + b.InitialFEN()
+
+	if len(b.Moves) > 0 {
+		moves := make([]string, len(b.Moves))
+		for i, m := range b.Moves {
+			moves[i] = m.UCI()
+		}
+		cmd += " moves " + strings.Join(moves, " ")
+	}
+
+	return e.send("%s", cmd)
+}
+
+func (e *Engine) send(format string, args ...any) error {
+	if _, err := fmt.Fprintf(e.stdin, format+"\n", args...); err != nil {
+		return fmt.Errorf("failed to send command to engine: %v", err)
+	}
+	return nil
+}
+
+// readUntil passes all lines of the engine to handle until it returns true.
+func (e *Engine) readUntil(timeout time.Duration, handle func(string) bool) error {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case line, ok := <-e.lines:
+			if !ok {
+				return errors.New("engine exited")
+			}
+			if line != "" && handle(line) {
+				return nil
+			}
+		case <-timer.C:
+			return errors.New("engine timed out")
+		}
+	}
+}
+
+func (e *Engine) read(stdout io.Reader) {
+	defer close(e.lines)
+
+	scanner := bufio.NewScanner(stdout)
+	for scanner.Scan() {
+		select {
+		case e.lines <- strings.TrimSpace(scanner.Text()):
+		case <-e.done:
+			return
+		}
+	}
+}
+
+// parseInfo updates the result with the search info of the engine
+// like "depth 12 score cp 31 nodes 24513 pv e2e4 e7e5".
+func parseInfo(fields []string, res *Result, pv *[]string) {
+	for i := 0; i < len(fields); i++ {
+		next := func() int {
+			if i+1 >= len(fields) {
+				return 0
+			}
+			i++
+			n, _ := strconv.Atoi(fields[i])
+			return n
+		}
+
+		switch fields[i] {
+		case "depth":
+			res.Depth = next()
+		case "nodes":
+			res.Nodes = next()
+		case "score":
+			if i+1 >= len(fields) {
+				return
+			}
+			i++
+			switch fields[i] {
+			case "cp":
+				res.Score, res.Mate = next(), 0
+			case "mate":
+				res.Score, res.Mate = 0, next()
+			}
+		case "pv":
+			*pv = fields[i+1:]
+			return
+		case "string":
+			// rest of the line is free text
+			return
+		}
+	}
+}
+
+// parsePV converts the moves of a principal variation in UCI notation
+// up to the first move that is invalid on the board.
+func parsePV(b *chess.Board, pv []string) []chess.Move {
+	var moves []chess.Move
+
+	b = b.Clone()
+	for _, uci := range pv {
+		m, err := b.Move(uci)
+		if err != nil {
+			break
+		}
+		moves = append(moves, m)
+	}
+
+	return moves
+}
@@ -0,0 +1,131 @@
+package uci_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ekzyis/chessbot/chess"
+	"github.com/ekzyis/chessbot/uci"
+	"github.com/stretchr/testify/assert"
+)
+
+func startEngine(t *testing.T) *uci.Engine {
+	e, err := uci.Start("testdata/engine.sh")
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	t.Cleanup(func() { e.Close() })
+
+	e.Timeout = time.Second
+
+	return e
+}
+
+func TestStart(t *testing.T) {
+	t.Parallel()
+
+	e := startEngine(t)
+
+	assert.Equal(t, "Fake Engine 1.0", e.Name)
+	assert.Equal(t, "chessbot", e.Author)
+
+	assert.NoError(t, e.SetOption("Skill Level", "10"))
+	assert.NoError(t, e.NewGame())
+	assert.NoError(t, e.Close())
+
+	_, err := uci.Start("testdata/missing.sh")
+	assert.ErrorContains(t, err, "failed to start engine testdata/missing.sh")
+}
+
+func TestEngineSearch(t *testing.T) {
+	t.Parallel()
+
+	e := startEngine(t)
+
+	b := chess.NewBoard()
+
+	res, err := e.Search(b, 10*time.Millisecond)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "e4", res.Move.SAN)
+		assert.Equal(t, 31, res.Score)
+		assert.Equal(t, 0, res.Mate)
+		assert.Equal(t, 2, res.Depth)
+		assert.Equal(t, 142, res.Nodes)
+		if assert.Len(t, res.PV, 2) {
+			assert.Equal(t, "e5", res.PV[1].SAN)
+		}
+	}
+
+	// searching does not change the board
+	assert.Equal(t, chess.StartFEN, b.FEN())
+
+	// engine receives moves played so far
+	_, err = b.Move(res.Move.UCI())
+	assert.NoError(t, err)
+
+	res, err = e.Search(b, 10*time.Millisecond)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "e5", res.Move.SAN)
+		assert.Equal(t, -25, res.Score)
+		// PV stops at invalid moves
+		assert.Len(t, res.PV, 3)
+	}
+}
+
+func TestEngineSearchMate(t *testing.T) {
+	t.Parallel()
+
+	e := startEngine(t)
+
+	b, err := chess.NewBoardFromFEN("6k1/5ppp/8/8/8/8/8/R5K1 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	m, err := e.BestMove(b, 10*time.Millisecond)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Ra8#", m.SAN)
+	}
+
+	res, err := e.Search(b, 10*time.Millisecond)
+	if assert.NoError(t, err) {
+		assert.Equal(t, 1, res.Mate)
+		assert.Equal(t, 0, res.Score)
+	}
+
+	_, err = b.Move("Ra2")
+	assert.NoError(t, err)
+
+	_, err = e.Search(b, 10*time.Millisecond)
+	assert.ErrorContains(t, err, "engine played invalid move e2e4")
+}
+
+func TestEngineSearchErrors(t *testing.T) {
+	t.Parallel()
+
+	e := startEngine(t)
+
+	b, err := chess.NewBoardFromFEN("R5k1/5ppp/8/8/8/8/8/6K1 b - - 1 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	_, err = e.Search(b, 10*time.Millisecond)
+	assert.EqualError(t, err, "engine found no move")
+
+	// engine that only answers after it was told to stop
+	b, err = chess.NewBoardFromFEN("4k3/8/8/8/8/8/4P3/4K3 w - - 0 1")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	e.Timeout = 100 * time.Millisecond
+	m, err := e.BestMove(b, 10*time.Millisecond)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Kd1", m.SAN)
+	}
+
+	assert.NoError(t, e.Close())
+	_, err = e.Search(b, 10*time.Millisecond)
+	assert.Error(t, err)
+}